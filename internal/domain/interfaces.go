@@ -1,19 +1,147 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type AppointmentRepo interface {
 	Create(ctx context.Context, appt *Appointment) error
 	GetById(ctx context.Context, id uint) (*Appointment, error)
-	List(ctx context.Context) ([]Appointment, error)
+	List(ctx context.Context, opts AppointmentListOptions) ([]Appointment, string, error)
+	ListBySeries(ctx context.Context, seriesID uint) ([]Appointment, error)
+	FindOverlapping(ctx context.Context, barberID uint, start, end time.Time, excludeID uint) ([]Appointment, error)
+	CountByClientStatus(ctx context.Context, clientID uint, status AppointmentStatus, since time.Time) (int64, error)
 	Update(ctx context.Context, appt *Appointment) error
+	// UpdateWithFee guarda la cita y registra el cargo en la misma transaccion
+	UpdateWithFee(ctx context.Context, appt *Appointment, fee *Fee) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// DueCheck vuelve a verificar el pendiente de una cita mientras su fila esta
+// bloqueada, para que dos pagos simultaneos no la paguen de mas. Recibe un
+// contexto con la transaccion del pago, asi la lectura ocurre dentro de ella.
+type DueCheck func(ctx context.Context) error
+
+// Transactor ejecuta operaciones de varios repositorios en una misma
+// transaccion. Los repositorios llamados con el contexto que recibe fn
+// participan de ella.
+type Transactor interface {
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type AppointmentSeriesRepo interface {
+	Create(ctx context.Context, series *AppointmentSeries) error
+	GetById(ctx context.Context, id uint) (*AppointmentSeries, error)
 	Delete(ctx context.Context, id uint) error
 }
 
 type ProductRepo interface {
 	Create(ctx context.Context, prod *Product) error
 	GetById(ctx context.Context, id uint) (*Product, error)
-	List(ctx context.Context) ([]Product, error)
+	List(ctx context.Context, opts ProductListOptions) ([]Product, string, error)
 	Update(ctx context.Context, prod *Product) error
 	Delete(ctx context.Context, id uint) error
 }
+
+type BarberRepo interface {
+	Create(ctx context.Context, barber *Barber) error
+	GetById(ctx context.Context, id uint) (*Barber, error)
+	List(ctx context.Context) ([]Barber, error)
+	Update(ctx context.Context, barber *Barber) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type CalendarRepo interface {
+	ListWeeklyHours(ctx context.Context) ([]BusinessHours, error)
+	GetWeeklyHours(ctx context.Context, weekday int) (*BusinessHours, error)
+	SaveWeeklyHours(ctx context.Context, hours *BusinessHours) error
+	CreateException(ctx context.Context, exc *CalendarException) error
+	GetExceptionById(ctx context.Context, id uint) (*CalendarException, error)
+	GetExceptionByDate(ctx context.Context, date time.Time) (*CalendarException, error)
+	ListExceptions(ctx context.Context, from, to time.Time) ([]CalendarException, error)
+	UpdateException(ctx context.Context, exc *CalendarException) error
+	DeleteException(ctx context.Context, id uint) error
+}
+
+type WaitlistRepo interface {
+	Create(ctx context.Context, entry *WaitlistEntry) error
+	GetById(ctx context.Context, id uint) (*WaitlistEntry, error)
+	List(ctx context.Context, status WaitlistStatus) ([]WaitlistEntry, error)
+	ListByClient(ctx context.Context, clientID uint) ([]WaitlistEntry, error)
+	Update(ctx context.Context, entry *WaitlistEntry) error
+	// UpdateFrom guarda la solicitud solo si sigue en alguno de los estados
+	// indicados; si no devuelve ErrorWaitlistChanged
+	UpdateFrom(ctx context.Context, entry *WaitlistEntry, from ...WaitlistStatus) error
+	// LockBarber bloquea las ofertas del barbero hasta el fin de la transaccion
+	LockBarber(ctx context.Context, barberID uint) error
+	FindCandidates(ctx context.Context, barberID uint, start, end time.Time) ([]WaitlistEntry, error)
+	FindActiveHolds(ctx context.Context, barberID uint, start, end, now time.Time) ([]WaitlistEntry, error)
+	ClaimExpiredHolds(ctx context.Context, now time.Time) ([]WaitlistEntry, error)
+	ExpirePastWindows(ctx context.Context, now time.Time) error
+}
+
+type ClientRepo interface {
+	Create(ctx context.Context, client *Client) error
+	GetById(ctx context.Context, id uint) (*Client, error)
+	List(ctx context.Context, search string) ([]Client, error)
+	Update(ctx context.Context, client *Client) error
+	Delete(ctx context.Context, id uint) error
+	Merge(ctx context.Context, survivor *Client, duplicateID uint) error
+	Erase(ctx context.Context, client *Client) error
+}
+
+type FeeRepo interface {
+	Create(ctx context.Context, fee *Fee) error
+	ListByClient(ctx context.Context, clientID uint) ([]Fee, error)
+}
+
+type LoyaltyRepo interface {
+	ListByClient(ctx context.Context, clientID uint) ([]LoyaltyEntry, error)
+	ListByAppointment(ctx context.Context, apptID uint) ([]LoyaltyEntry, error)
+	Balance(ctx context.Context, clientID uint) (int, error)
+	Create(ctx context.Context, entry *LoyaltyEntry) error
+	// Redeem registra un canje solo si el saldo del cliente alcanza y check
+	// aprueba el descuento con la cita bloqueada
+	Redeem(ctx context.Context, entry *LoyaltyEntry, check DueCheck) error
+}
+
+type VoucherRepo interface {
+	Create(ctx context.Context, voucher *Voucher) error
+	GetByCode(ctx context.Context, code string) (*Voucher, error)
+	ListTransactions(ctx context.Context, apptID uint) ([]VoucherTransaction, error)
+	// ListTransactionsByClient devuelve los movimientos de tarjetas de regalo
+	// en las citas del cliente
+	ListTransactionsByClient(ctx context.Context, clientID uint) ([]VoucherTransaction, error)
+	// Redeem descuenta el saldo solo si alcanza, la tarjeta no vencio y
+	// check aprueba el pago con la cita bloqueada
+	Redeem(ctx context.Context, tx *VoucherTransaction, now time.Time, check DueCheck) error
+	// Refund devuelve el saldo canjeado a la tarjeta
+	Refund(ctx context.Context, tx *VoucherTransaction) error
+}
+
+type PromotionRepo interface {
+	Create(ctx context.Context, promo *Promotion) error
+	GetById(ctx context.Context, id uint) (*Promotion, error)
+	GetByCode(ctx context.Context, code string) (*Promotion, error)
+	List(ctx context.Context) ([]Promotion, error)
+	Update(ctx context.Context, promo *Promotion) error
+	// Redeem registra el uso solo si la promocion no alcanzo sus limites
+	Redeem(ctx context.Context, redemption *PromotionRedemption) error
+	// CountUses cuenta los usos vigentes de la promocion, de todos los
+	// clientes o solo del indicado
+	CountUses(ctx context.Context, promoID, clientID uint) (int64, error)
+	// GetRedemption devuelve la promocion vigente aplicada a la cita
+	GetRedemption(ctx context.Context, apptID uint) (*PromotionRedemption, error)
+	ListRedemptionsByClient(ctx context.Context, clientID uint) ([]PromotionRedemption, error)
+	Release(ctx context.Context, apptID uint, now time.Time) error
+}
+
+type ReceiptRepo interface {
+	GetByAppointment(ctx context.Context, apptID uint) (*Receipt, error)
+	// ListByClient devuelve los recibos de las citas del cliente
+	ListByClient(ctx context.Context, clientID uint) ([]Receipt, error)
+	// Issue asigna el siguiente numero al recibo y lo guarda con lo impreso,
+	// o devuelve el recibo que la cita ya tenia
+	Issue(ctx context.Context, receipt *Receipt) (*Receipt, error)
+}
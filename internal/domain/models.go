@@ -6,25 +6,448 @@ import (
 )
 
 var (
-	ErrorNotFound     = errors.New("record not found")
-	ErrorInvalidInput = errors.New("invalid input")
+	ErrorNotFound          = errors.New("record not found")
+	ErrorInvalidInput      = errors.New("invalid input")
+	ErrorInvalidTransition = errors.New("invalid status transition")
+	ErrorOverlap           = errors.New("el turno se solapa con otro existente del mismo barbero")
+	ErrorInvalidToken      = errors.New("token invalido o vencido")
+	ErrorCancelCutoff      = errors.New("ya paso el plazo para cancelar la cita")
+	ErrorBookingBlocked    = errors.New("el cliente no puede agendar por inasistencias repetidas")
+	ErrorDepositRequired   = errors.New("la cita requiere el pago de la sena")
+	ErrorInsufficientFunds = errors.New("saldo insuficiente")
+	ErrorVoucherExpired    = errors.New("la tarjeta de regalo esta vencida")
+	ErrorDuplicateCode     = errors.New("ya existe una tarjeta de regalo con ese codigo")
+	ErrorDuplicatePromo    = errors.New("ya existe una promocion con ese codigo")
+	ErrorPromotionLimit    = errors.New("la promocion alcanzo su limite de usos")
+	ErrorPromotionApplied  = errors.New("la cita ya tiene una promocion aplicada")
+	ErrorNotCompleted      = errors.New("solo se emiten recibos de citas completadas")
+	ErrorWaitlistChanged   = errors.New("la solicitud de lista de espera cambio de estado")
+	ErrorBarberHasAppts    = errors.New("no se puede eliminar un barbero con citas registradas, desactivelo")
+)
+
+type AppointmentStatus string
+
+// Estados del ciclo de vida de una cita
+const (
+	StatusScheduled  AppointmentStatus = "scheduled"
+	StatusConfirmed  AppointmentStatus = "confirmed"
+	StatusCheckedIn  AppointmentStatus = "checked_in"
+	StatusInProgress AppointmentStatus = "in_progress"
+	StatusCompleted  AppointmentStatus = "completed"
+	StatusNoShow     AppointmentStatus = "no_show"
+	StatusCancelled  AppointmentStatus = "cancelled"
 )
 
 type Appointment struct {
-	ID         uint      `gorm:"primaryKey" json:"id"`
-	ClientName string    `gorm:"size:100;not null" json:"client_name"`
-	StartTime  time.Time `gorm:"not null" json:"start_time"`
-	EndTime    time.Time `gorm:"not null" json:"end_time"`
-	Products   []Product `gorm:"many2many:appointment_products;" json:"products"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID          uint              `gorm:"primaryKey" json:"id"`
+	ClientID    uint              `gorm:"not null;index" json:"client_id"`
+	Client      *Client           `json:"client,omitempty"`
+	BarberID    uint              `gorm:"not null;index" json:"barber_id"`
+	Barber      *Barber           `json:"barber,omitempty"`
+	StartTime   time.Time         `gorm:"not null" json:"start_time"`
+	EndTime     time.Time         `gorm:"not null" json:"end_time"`
+	Status      AppointmentStatus `gorm:"size:20;not null;default:scheduled;index" json:"status"`
+	ConfirmedAt *time.Time        `json:"confirmed_at,omitempty"`
+	CheckedInAt *time.Time        `json:"checked_in_at,omitempty"`
+	StartedAt   *time.Time        `json:"started_at,omitempty"`
+	CompletedAt *time.Time        `json:"completed_at,omitempty"`
+	NoShowAt    *time.Time        `json:"no_show_at,omitempty"`
+	CancelledAt *time.Time        `json:"cancelled_at,omitempty"`
+	// Deposit es la sena exigida al agendar, cero si no se exige
+	Deposit       Money      `gorm:"embedded;embeddedPrefix:deposit_" json:"deposit"`
+	DepositPaidAt *time.Time `json:"deposit_paid_at,omitempty"`
+	SeriesID      *uint      `gorm:"index" json:"series_id,omitempty"`
+	// TokenVersion cambia al reemitir el token de gestion y revoca los anteriores
+	TokenVersion uint              `gorm:"not null;default:0" json:"-"`
+	Items        []AppointmentItem `gorm:"foreignKey:AppointmentID;constraint:OnDelete:CASCADE" json:"items"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+	// ManageToken solo se devuelve al agendar, no se guarda
+	ManageToken string `gorm:"-" json:"manage_token,omitempty"`
+}
+
+// AppointmentItem es una linea de la cita. Guarda el nombre y el precio del
+// producto al momento de agendar, para que los cambios de precio no alteren
+// el total de citas ya registradas. ProductID queda como referencia y es nulo
+// en las lineas libres, que solo tienen descripcion y precio.
+type AppointmentItem struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	AppointmentID uint   `gorm:"not null;index" json:"appointment_id"`
+	ProductID     *uint  `gorm:"index" json:"product_id"`
+	Name          string `gorm:"size:100;not null" json:"name"`
+	Category      string `gorm:"size:50" json:"category,omitempty"`
+	UnitPrice     Money  `gorm:"embedded;embeddedPrefix:unit_price_" json:"unit_price"`
+	Quantity      int    `gorm:"not null;default:1" json:"quantity"`
+	Tax           Tax    `gorm:"embedded;embeddedPrefix:tax_" json:"tax"`
+	// ListPrice es el precio de lista cuando se cobro otro precio en la linea
+	ListPrice      Money     `gorm:"embedded;embeddedPrefix:list_price_" json:"list_price"`
+	OverrideReason string    `gorm:"size:200" json:"override_reason,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Total es el precio de la linea: precio unitario por cantidad
+func (i AppointmentItem) Total() Money {
+	return i.UnitPrice.Mul(i.Quantity)
+}
+
+// IsCustom indica si la linea es libre, sin producto del catalogo
+func (i AppointmentItem) IsCustom() bool {
+	return i.ProductID == nil
+}
+
+// IsOverridden indica si se cobro un precio distinto al de lista
+func (i AppointmentItem) IsOverridden() bool {
+	return i.OverrideReason != ""
+}
+
+// AppointmentSeries agrupa las citas generadas por una regla de recurrencia
+// (subconjunto de RRULE de RFC 5545)
+type AppointmentSeries struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	RRule     string    `gorm:"column:rrule;size:200;not null" json:"rrule"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OccurrenceConflict describe una ocurrencia de una serie que no pudo agendarse
+type OccurrenceConflict struct {
+	AppointmentID uint      `json:"appointment_id,omitempty"`
+	StartTime     time.Time `json:"start_time"`
+	Error         string    `json:"error"`
 }
 
 type Product struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	Name        string    `gorm:"size:100;not null" json:"name"`
-	Price       float64   `gorm:"not null" json:"price"`
-	Description string    `gorm:"size:500" json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	Name            string    `gorm:"size:100;not null" json:"name"`
+	Price           Money     `gorm:"embedded;embeddedPrefix:price_" json:"price"`
+	Description     string    `gorm:"size:500" json:"description"`
+	Category        string    `gorm:"size:50;index" json:"category"`
+	Tax             Tax       `gorm:"embedded;embeddedPrefix:tax_" json:"tax"`
+	DurationMinutes int       `gorm:"not null;default:0" json:"duration_minutes"`
+	BufferMinutes   int       `gorm:"not null;default:0" json:"buffer_minutes"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type Client struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	Name      string     `gorm:"size:100;not null" json:"name"`
+	Phone     string     `gorm:"size:30;index" json:"phone"`
+	Email     string     `gorm:"size:150;index" json:"email"`
+	Birthday  *time.Time `gorm:"type:date" json:"birthday,omitempty"`
+	Notes     string     `gorm:"type:text" json:"notes"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	// ErasedAt indica que los datos personales fueron anonimizados
+	ErasedAt *time.Time `json:"erased_at,omitempty"`
+	// NoShowCount se calcula al consultar, no se guarda
+	NoShowCount int `gorm:"->;-:migration" json:"no_show_count"`
+}
+
+// ClientExport reune todo lo que se guarda de un cliente para responder
+// solicitudes de acceso a sus datos
+type ClientExport struct {
+	ExportedAt   time.Time             `json:"exported_at"`
+	Client       Client                `json:"client"`
+	Appointments []Appointment         `json:"appointments"`
+	Waitlist     []WaitlistEntry       `json:"waitlist"`
+	Fees         []Fee                 `json:"fees"`
+	Loyalty      []LoyaltyEntry        `json:"loyalty"`
+	Promotions   []PromotionRedemption `json:"promotions"`
+	Vouchers     []VoucherTransaction  `json:"vouchers"`
+	Receipts     []Receipt             `json:"receipts"`
+}
+
+// DuplicateCandidate es un par de clientes que probablemente son la misma persona
+type DuplicateCandidate struct {
+	Client    Client   `json:"client"`
+	Duplicate Client   `json:"duplicate"`
+	Score     float64  `json:"score"`
+	Reasons   []string `json:"reasons"`
+}
+
+// ClientHistory resume las visitas pasadas de un cliente
+type ClientHistory struct {
+	ClientID                 uint           `json:"client_id"`
+	Visits                   int            `json:"visits"`
+	TotalSpent               Money          `json:"total_spent"`
+	AverageVisitIntervalDays *float64       `json:"average_visit_interval_days"`
+	LastVisit                *time.Time     `json:"last_visit"`
+	LastBarber               *Barber        `json:"last_barber"`
+	FavouriteServices        []ServiceCount `json:"favourite_services"`
+	Appointments             []Appointment  `json:"appointments"`
+}
+
+// ServiceCount indica cuantas veces se tomo un servicio
+type ServiceCount struct {
+	ProductID uint   `json:"product_id"`
+	Name      string `json:"name"`
+	Count     int    `json:"count"`
+}
+
+type Barber struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"size:100;not null" json:"name"`
+	Phone     string    `gorm:"size:30" json:"phone"`
+	Active    bool      `gorm:"not null" json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BusinessHours es el horario de atencion de un dia de la semana (0 = domingo).
+// Las horas se guardan en formato HH:MM.
+type BusinessHours struct {
+	Weekday   int       `gorm:"primaryKey;autoIncrement:false" json:"weekday"`
+	Closed    bool      `gorm:"not null;default:false" json:"closed"`
+	OpenTime  string    `gorm:"size:5" json:"open_time"`
+	CloseTime string    `gorm:"size:5" json:"close_time"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Tipos de excepcion del calendario
+const (
+	CalendarHoliday  = "holiday"
+	CalendarClosure  = "closure"
+	CalendarOverride = "override"
+)
+
+// CalendarException reemplaza el horario semanal en una fecha puntual:
+// festivos y cierres especiales cierran el dia, override cambia el horario
+type CalendarException struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Date      time.Time `gorm:"type:date;not null;uniqueIndex" json:"date"`
+	Kind      string    `gorm:"size:20;not null" json:"kind"`
+	OpenTime  string    `gorm:"size:5" json:"open_time,omitempty"`
+	CloseTime string    `gorm:"size:5" json:"close_time,omitempty"`
+	Reason    string    `gorm:"size:200" json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WaitlistStatus string
+
+// Estados de una solicitud en lista de espera
+const (
+	WaitlistWaiting   WaitlistStatus = "waiting"
+	WaitlistOffered   WaitlistStatus = "offered"
+	WaitlistAccepted  WaitlistStatus = "accepted"
+	WaitlistCancelled WaitlistStatus = "cancelled"
+	WaitlistExpired   WaitlistStatus = "expired"
+)
+
+// FeeReason indica por que se genero un cargo al cliente
+type FeeReason string
+
+const (
+	FeeLateCancel FeeReason = "late_cancel"
+	FeeNoShow     FeeReason = "no_show"
+)
+
+// Fee es un cargo generado al cliente por la politica de cancelacion
+type Fee struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ClientID      uint      `gorm:"not null;index" json:"client_id"`
+	AppointmentID uint      `gorm:"not null;index" json:"appointment_id"`
+	Reason        FeeReason `gorm:"size:20;not null" json:"reason"`
+	Percent       int       `gorm:"not null" json:"percent"`
+	Amount        Money     `gorm:"embedded;embeddedPrefix:amount_" json:"amount"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// LoyaltyKind es el tipo de movimiento de puntos
+type LoyaltyKind string
+
+const (
+	LoyaltyEarn   LoyaltyKind = "earn"
+	LoyaltyRedeem LoyaltyKind = "redeem"
+	LoyaltyRefund LoyaltyKind = "refund"
+)
+
+// LoyaltyEntry es un movimiento del libro de puntos del cliente. Los puntos
+// ganados y devueltos son positivos, los canjeados negativos. Amount es el
+// descuento que representa un canje o su devolucion.
+type LoyaltyEntry struct {
+	ID            uint        `gorm:"primaryKey" json:"id"`
+	ClientID      uint        `gorm:"not null;index" json:"client_id"`
+	AppointmentID *uint       `gorm:"index" json:"appointment_id,omitempty"`
+	Kind          LoyaltyKind `gorm:"size:20;not null" json:"kind"`
+	Points        int         `gorm:"not null" json:"points"`
+	Amount        Money       `gorm:"embedded;embeddedPrefix:amount_" json:"amount"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+// TotalLine es una linea del detalle del total de una cita: un servicio o
+// producto cobrado, un descuento o un pago
+type TotalLine struct {
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity,omitempty"`
+	UnitPrice   *Money `json:"unit_price,omitempty"`
+	Note        string `json:"note,omitempty"`
+	Amount      Money  `json:"amount"`
+	// ItemIDs son las lineas de la cita sobre las que aplica un descuento;
+	// vacio aplica sobre toda la cita
+	ItemIDs []uint `json:"-"`
+}
+
+// AppointmentTotal es el detalle del total de una cita. Subtotal suma las
+// lineas al precio cobrado; los impuestos se calculan por tarifa sobre las
+// lineas con los descuentos ya repartidos, y Total suma a lo cobrado el
+// impuesto de los precios que no lo incluyen. Due es lo que queda por pagar
+// despues de los pagos ya registrados.
+type AppointmentTotal struct {
+	AppointmentID uint        `json:"appoinmet_id"`
+	Lines         []TotalLine `json:"lines"`
+	Subtotal      Money       `json:"subtotal"`
+	Discounts     []TotalLine `json:"discounts"`
+	Taxes         []TaxLine   `json:"taxes"`
+	TaxTotal      Money       `json:"tax_total"`
+	Total         Money       `json:"total"`
+	Payments      []TotalLine `json:"payments"`
+	Due           Money       `json:"due"`
+}
+
+type DiscountKind string
+
+// Tipos de descuento de una promocion
+const (
+	DiscountPercent DiscountKind = "percent"
+	DiscountFixed   DiscountKind = "fixed"
+)
+
+// Promotion es un codigo promocional con un descuento porcentual o fijo. Si
+// tiene producto o categoria solo descuenta sobre esas lineas de la cita. Las
+// fechas de vigencia y los dias de la semana se comparan con el inicio de la
+// cita. Los limites en cero no restringen.
+type Promotion struct {
+	ID               uint           `gorm:"primaryKey" json:"id"`
+	Code             string         `gorm:"size:32;not null;uniqueIndex" json:"code"`
+	Description      string         `gorm:"size:200" json:"description"`
+	Kind             DiscountKind   `gorm:"size:20;not null" json:"kind"`
+	Percent          int            `gorm:"not null;default:0" json:"percent,omitempty"`
+	Amount           Money          `gorm:"embedded;embeddedPrefix:amount_" json:"amount"`
+	ProductID        *uint          `gorm:"index" json:"product_id,omitempty"`
+	Category         string         `gorm:"size:50" json:"category,omitempty"`
+	ValidFrom        *time.Time     `json:"valid_from,omitempty"`
+	ValidUntil       *time.Time     `json:"valid_until,omitempty"`
+	Weekdays         []time.Weekday `gorm:"serializer:json" json:"weekdays,omitempty"`
+	MaxUses          int            `gorm:"not null;default:0" json:"max_uses"`
+	MaxUsesPerClient int            `gorm:"not null;default:0" json:"max_uses_per_client"`
+	Active           bool           `gorm:"not null;default:true" json:"active"`
+	// Uses se calcula al consultar, no se guarda
+	Uses      int       `gorm:"->;-:migration" json:"uses"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PromotionRedemption registra el uso de una promocion en una cita. Al
+// cancelar la cita se libera y deja de contar para los limites.
+type PromotionRedemption struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	PromotionID   uint       `gorm:"not null;index" json:"promotion_id"`
+	Promotion     *Promotion `json:"promotion,omitempty"`
+	AppointmentID uint       `gorm:"not null;index" json:"appointment_id"`
+	ClientID      uint       `gorm:"not null;index" json:"client_id"`
+	ReleasedAt    *time.Time `json:"released_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// Receipt es el recibo emitido para una cita completada. Los numeros son
+// consecutivos y sin saltos: se asignan al emitirlo y no se reutilizan.
+type Receipt struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	Number        int64     `gorm:"not null;uniqueIndex" json:"number"`
+	AppointmentID uint      `gorm:"not null;uniqueIndex" json:"appointment_id"`
+	IssuedAt      time.Time `gorm:"not null" json:"issued_at"`
+	// Lo impreso se guarda al emitir el recibo para que no cambie con ajustes
+	// posteriores de la cita. Los pagos se leen al servirlo.
+	ClientName       string            `gorm:"size:100" json:"client_name"`
+	BarberName       string            `gorm:"size:100" json:"barber_name"`
+	AppointmentStart time.Time         `json:"appointment_start"`
+	Total            *AppointmentTotal `gorm:"serializer:json" json:"total"`
+	CreatedAt        time.Time         `json:"created_at"`
+}
+
+// Voucher es una tarjeta de regalo o bono prepago que se canjea, total o
+// parcialmente, contra el total de las citas
+type Voucher struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	Code           string     `gorm:"size:32;not null;uniqueIndex" json:"code"`
+	InitialBalance Money      `gorm:"embedded;embeddedPrefix:initial_balance_" json:"initial_balance"`
+	Balance        Money      `gorm:"embedded;embeddedPrefix:balance_" json:"balance"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// VoucherTxKind es el tipo de movimiento de una tarjeta de regalo
+type VoucherTxKind string
+
+const (
+	VoucherRedeem VoucherTxKind = "redeem"
+	VoucherRefund VoucherTxKind = "refund"
+)
+
+// VoucherTransaction es un canje o devolucion de saldo de una tarjeta en una cita
+type VoucherTransaction struct {
+	ID            uint          `gorm:"primaryKey" json:"id"`
+	VoucherID     uint          `gorm:"not null;index" json:"voucher_id"`
+	Voucher       *Voucher      `json:"voucher,omitempty"`
+	AppointmentID uint          `gorm:"not null;index" json:"appointment_id"`
+	Kind          VoucherTxKind `gorm:"size:20;not null" json:"kind"`
+	Amount        Money         `gorm:"embedded;embeddedPrefix:amount_" json:"amount"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+// WaitlistEntry es un cliente esperando un turno dentro de una ventana de
+// tiempo. Cuando se libera un horario se le ofrece con una reserva temporal
+// (Offer*) que vence en HoldExpiresAt.
+type WaitlistEntry struct {
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	ClientID      uint           `gorm:"not null;index" json:"client_id"`
+	Client        *Client        `json:"client,omitempty"`
+	BarberID      *uint          `gorm:"index" json:"barber_id,omitempty"`
+	WindowStart   time.Time      `gorm:"not null" json:"window_start"`
+	WindowEnd     time.Time      `gorm:"not null" json:"window_end"`
+	Products      []Product      `gorm:"many2many:waitlist_products;" json:"products"`
+	Status        WaitlistStatus `gorm:"size:20;not null;default:waiting;index" json:"status"`
+	OfferBarberID *uint          `json:"offer_barber_id,omitempty"`
+	OfferStart    *time.Time     `json:"offer_start,omitempty"`
+	OfferEnd      *time.Time     `json:"offer_end,omitempty"`
+	HoldExpiresAt *time.Time     `json:"hold_expires_at,omitempty"`
+	AppointmentID *uint          `json:"appointment_id,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// AvailableSlot es un horario libre en el que se puede reservar un turno
+type AvailableSlot struct {
+	BarberID  uint      `json:"barber_id"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// AppointmentListOptions filtra, ordena y pagina el listado de citas.
+// Los campos en cero no filtran; Limit en cero devuelve todos los resultados.
+type AppointmentListOptions struct {
+	From      *time.Time
+	To        *time.Time
+	BarberID  uint
+	ClientID  uint
+	Status    AppointmentStatus
+	ProductID uint
+	SortDesc  bool
+	Cursor    string
+	Limit     int
+}
+
+// ProductListOptions filtra, ordena y pagina el listado de productos.
+// SortBy acepta id, name o price.
+type ProductListOptions struct {
+	Name     string
+	Category string
+	SortBy   string
+	SortDesc bool
+	Cursor   string
+	Limit    int
 }
@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultCurrency es la moneda de la barberia. Se configura al iniciar y se
+// usa cuando un importe no indica moneda.
+var DefaultCurrency = "COP"
+
+var ErrorCurrencyMismatch = errors.New("los importes tienen monedas distintas")
+
+// Money es un importe exacto en centavos de una moneda ISO 4217. Las
+// operaciones asumen importes de la misma moneda; quien combina importes de
+// distinto origen debe verificarlo antes con SameCurrency.
+type Money struct {
+	Cents    int64  `gorm:"column:cents;not null;default:0" json:"cents"`
+	Currency string `gorm:"column:currency;size:3;not null" json:"currency"`
+}
+
+func NewMoney(cents int64, currency string) Money {
+	return Money{cents, currency}
+}
+
+// Add suma dos importes. Un importe sin moneda toma la del otro.
+func (m Money) Add(o Money) Money {
+	return Money{m.Cents + o.Cents, m.currencyWith(o)}
+}
+
+func (m Money) Sub(o Money) Money {
+	return Money{m.Cents - o.Cents, m.currencyWith(o)}
+}
+
+// Mul multiplica el importe por una cantidad entera
+func (m Money) Mul(n int) Money {
+	return Money{m.Cents * int64(n), m.Currency}
+}
+
+// Percent devuelve el porcentaje del importe redondeado al centavo, con los
+// medios centavos alejandose de cero
+func (m Money) Percent(percent int) Money {
+	return Money{divRound(m.Cents*int64(percent), 100), m.Currency}
+}
+
+// Rate aplica una tasa en puntos basicos (1900 = 19%) redondeando al centavo
+func (m Money) Rate(bps int) Money {
+	return Money{divRound(m.Cents*int64(bps), 10000), m.Currency}
+}
+
+// WithoutRate quita de un importe que ya incluye la tasa en puntos basicos la
+// parte que le corresponde, devolviendo la base redondeada al centavo
+func (m Money) WithoutRate(bps int) Money {
+	return Money{divRound(m.Cents*10000, 10000+int64(bps)), m.Currency}
+}
+
+// Allocate reparte el importe en proporcion a los pesos sin perder centavos:
+// los centavos sobrantes van a las partes con mayor resto. Con pesos en cero
+// devuelve partes en cero. Un importe negativo se reparte como el positivo
+// con el signo cambiado.
+func (m Money) Allocate(weights []int64) []Money {
+	if m.Cents < 0 {
+		parts := Money{-m.Cents, m.Currency}.Allocate(weights)
+		for i := range parts {
+			parts[i].Cents = -parts[i].Cents
+		}
+		return parts
+	}
+
+	parts := make([]Money, len(weights))
+	var total int64
+	for i, w := range weights {
+		parts[i].Currency = m.Currency
+		total += w
+	}
+	if total <= 0 {
+		return parts
+	}
+
+	remainders := make([]int64, len(weights))
+	var assigned int64
+	for i, w := range weights {
+		parts[i].Cents = m.Cents * w / total
+		remainders[i] = m.Cents * w % total
+		assigned += parts[i].Cents
+	}
+
+	for left := m.Cents - assigned; left > 0; left-- {
+		best := 0
+		for i := range remainders {
+			if remainders[i] > remainders[best] {
+				best = i
+			}
+		}
+		parts[best].Cents++
+		remainders[best] = -1
+	}
+
+	return parts
+}
+
+// Min devuelve el menor de los dos importes
+func (m Money) Min(o Money) Money {
+	if o.Cents < m.Cents {
+		return Money{o.Cents, m.currencyWith(o)}
+	}
+	return Money{m.Cents, m.currencyWith(o)}
+}
+
+// NonNegative devuelve cero si el importe es negativo
+func (m Money) NonNegative() Money {
+	if m.Cents < 0 {
+		return Money{0, m.Currency}
+	}
+	return m
+}
+
+func (m Money) IsZero() bool     { return m.Cents == 0 }
+func (m Money) IsPositive() bool { return m.Cents > 0 }
+func (m Money) IsNegative() bool { return m.Cents < 0 }
+
+// SameCurrency indica si los importes se pueden combinar. Un importe cero
+// sin moneda es compatible con cualquiera.
+func (m Money) SameCurrency(o Money) bool {
+	return m.Currency == o.Currency ||
+		(m.Currency == "" && m.Cents == 0) ||
+		(o.Currency == "" && o.Cents == 0)
+}
+
+// String formatea el importe con dos decimales, ej: 15000.00 COP
+func (m Money) String() string {
+	sign := ""
+	cents := m.Cents
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d.%02d %s", sign, cents/100, cents%100, m.Currency)
+}
+
+func (m Money) currencyWith(o Money) string {
+	if m.Currency == "" {
+		return o.Currency
+	}
+	return m.Currency
+}
+
+// ValidCurrency indica si el codigo tiene la forma de un codigo ISO 4217
+func ValidCurrency(currency string) bool {
+	if len(currency) != 3 {
+		return false
+	}
+	for _, r := range currency {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// divRound divide redondeando los medios alejandose de cero
+func divRound(n, d int64) int64 {
+	q, r := n/d, n%d
+	if 2*abs(r) >= abs(d) {
+		if (n < 0) != (d < 0) {
+			q--
+		} else {
+			q++
+		}
+	}
+	return q
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
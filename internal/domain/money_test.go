@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDivRound(t *testing.T) {
+	tests := []struct {
+		n, d, want int64
+	}{
+		{4, 2, 2},
+		{0, 7, 0},
+		{1, 3, 0},
+		{2, 3, 1},
+		{5, 2, 3},
+		{-5, 2, -3},
+		{5, -2, -3},
+		{-5, -2, 3},
+		{-1, 3, 0},
+		{-2, 3, -1},
+		{149, 100, 1},
+		{150, 100, 2},
+		{-150, 100, -2},
+	}
+
+	for _, tt := range tests {
+		if got := divRound(tt.n, tt.d); got != tt.want {
+			t.Errorf("divRound(%d, %d) = %d, se esperaba %d", tt.n, tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyPercentAndRate(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Money
+		want Money
+	}{
+		{"porcentaje exacto", NewMoney(20000, "COP").Percent(50), NewMoney(10000, "COP")},
+		{"medio centavo sube", NewMoney(15, "COP").Percent(10), NewMoney(2, "COP")},
+		{"medio centavo negativo baja", NewMoney(-15, "COP").Percent(10), NewMoney(-2, "COP")},
+		{"tasa exacta", NewMoney(10000, "COP").Rate(1900), NewMoney(1900, "COP")},
+		{"tasa con medio centavo", NewMoney(50, "COP").Rate(100), NewMoney(1, "COP")},
+		{"tasa redondea abajo", NewMoney(50, "COP").Rate(1900), NewMoney(10, "COP")},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: %v, se esperaba %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestMoneyWithoutRate(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount Money
+		bps    int
+		want   Money
+	}{
+		{"sin tasa", NewMoney(11900, "COP"), 0, NewMoney(11900, "COP")},
+		{"IVA 19% exacto", NewMoney(11900, "COP"), 1900, NewMoney(10000, "COP")},
+		{"IVA 19% redondeado", NewMoney(100, "COP"), 1900, NewMoney(84, "COP")},
+		{"medio centavo sube", NewMoney(3, "COP"), 10000, NewMoney(2, "COP")},
+		{"medio centavo negativo baja", NewMoney(-3, "COP"), 10000, NewMoney(-2, "COP")},
+		{"negativo", NewMoney(-11900, "COP"), 1900, NewMoney(-10000, "COP")},
+		{"cero", NewMoney(0, "COP"), 1900, NewMoney(0, "COP")},
+	}
+
+	for _, tt := range tests {
+		if got := tt.amount.WithoutRate(tt.bps); got != tt.want {
+			t.Errorf("%s: %v.WithoutRate(%d) = %v, se esperaba %v", tt.name, tt.amount, tt.bps, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  int64
+		weights []int64
+		want    []int64
+	}{
+		{"partes iguales exactas", 90, []int64{1, 1, 1}, []int64{30, 30, 30}},
+		{"sobrante al primero", 100, []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{"sobrantes repartidos", 7, []int64{1, 1, 1, 1}, []int64{2, 2, 2, 1}},
+		{"sobrante al mayor resto", 101, []int64{1, 2}, []int64{34, 67}},
+		{"pesos desiguales", 1000, []int64{3, 1}, []int64{750, 250}},
+		{"peso en cero", 5, []int64{3, 0, 2}, []int64{3, 0, 2}},
+		{"pesos en cero", 100, []int64{0, 0}, []int64{0, 0}},
+		{"sin pesos", 100, []int64{}, []int64{}},
+		{"importe cero", 0, []int64{1, 2}, []int64{0, 0}},
+		{"negativo", -100, []int64{1, 1, 1}, []int64{-34, -33, -33}},
+		{"negativo con sobrantes", -7, []int64{1, 1, 1, 1}, []int64{-2, -2, -2, -1}},
+	}
+
+	for _, tt := range tests {
+		parts := NewMoney(tt.amount, "COP").Allocate(tt.weights)
+
+		got := make([]int64, len(parts))
+		var sum int64
+		for i, part := range parts {
+			if part.Currency != "COP" {
+				t.Errorf("%s: la parte %d perdio la moneda: %v", tt.name, i, part)
+			}
+			got[i] = part.Cents
+			sum += part.Cents
+		}
+
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: Allocate(%v) = %v, se esperaba %v", tt.name, tt.weights, got, tt.want)
+		}
+
+		var weights int64
+		for _, w := range tt.weights {
+			weights += w
+		}
+		if weights > 0 && sum != tt.amount {
+			t.Errorf("%s: las partes suman %d, se esperaba %d", tt.name, sum, tt.amount)
+		}
+	}
+}
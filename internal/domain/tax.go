@@ -0,0 +1,53 @@
+package domain
+
+import "fmt"
+
+// DefaultTax es el impuesto de los productos y lineas que no indican uno. Se
+// configura al iniciar.
+var DefaultTax = Tax{}
+
+// Tax es el impuesto (IVA) de un producto. Rate esta en puntos basicos
+// (1900 = 19%). Included indica que el precio ya incluye el impuesto. Los
+// productos exentos no pagan impuesto y se informan aparte de la tarifa 0%.
+type Tax struct {
+	Rate     int  `gorm:"column:rate;not null;default:0" json:"rate_bps"`
+	Included bool `gorm:"column:included;not null;default:false" json:"included"`
+	Exempt   bool `gorm:"column:exempt;not null;default:false" json:"exempt"`
+}
+
+func (t Tax) Validate() error {
+	if t.Rate < 0 || t.Rate > 10000 {
+		return fmt.Errorf("%w: la tarifa de impuesto debe estar entre 0 y 10000 puntos basicos", ErrorInvalidInput)
+	}
+
+	if t.Exempt && t.Rate != 0 {
+		return fmt.Errorf("%w: un producto exento no puede tener tarifa de impuesto", ErrorInvalidInput)
+	}
+
+	return nil
+}
+
+// Split separa el importe cobrado en base gravable e impuesto. Si el precio
+// incluye el impuesto la base se obtiene descontandolo; si no, el impuesto
+// se suma a la base.
+func (t Tax) Split(amount Money) (base, tax Money) {
+	if t.Exempt || t.Rate == 0 {
+		return amount, Money{0, amount.Currency}
+	}
+
+	if t.Included {
+		base = amount.WithoutRate(t.Rate)
+		return base, amount.Sub(base)
+	}
+
+	return amount, amount.Rate(t.Rate)
+}
+
+// TaxLine es el impuesto de una cita para una tarifa: la base gravable y el
+// impuesto que le corresponde
+type TaxLine struct {
+	Rate   int   `json:"rate_bps"`
+	Exempt bool  `json:"exempt"`
+	Base   Money `json:"base"`
+	Tax    Money `json:"tax"`
+}
@@ -0,0 +1,74 @@
+package receipt
+
+import (
+	"html/template"
+	"io"
+)
+
+var htmlTemplate = template.Must(template.New("receipt").Funcs(template.FuncMap{
+	"taxLabel":   taxLabel,
+	"formatDate": formatDate,
+	"negative":   negative,
+}).Parse(`<!DOCTYPE html>
+<html lang="es">
+<head>
+<meta charset="utf-8">
+<title>Recibo {{.Number}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 14px; color: #222; max-width: 720px; margin: 2em auto; }
+h1 { font-size: 20px; margin: 0; }
+table { width: 100%; border-collapse: collapse; margin-top: 1em; }
+th, td { padding: 4px 6px; text-align: left; }
+th { border-bottom: 1px solid #222; }
+td.num, th.num { text-align: right; white-space: nowrap; }
+tr.total td { border-top: 1px solid #222; font-weight: bold; }
+.meta { margin-top: 1em; }
+.note { color: #666; font-size: 12px; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Shop.Name}}</h1>
+{{with .Shop.TaxID}}<div>NIT {{.}}</div>{{end}}
+{{with .Shop.Address}}<div>{{.}}</div>{{end}}
+{{with .Shop.Phone}}<div>Tel. {{.}}</div>{{end}}
+</header>
+
+<section class="meta">
+<div><strong>Recibo No. {{.Number}}</strong></div>
+<div>Fecha de emision: {{formatDate .IssuedAt}}</div>
+<div>Cliente: {{.ClientName}}</div>
+{{with .BarberName}}<div>Barbero: {{.}}</div>{{end}}
+<div>Cita: {{formatDate .AppointmentStart}}</div>
+</section>
+
+<table>
+<thead>
+<tr><th>Descripcion</th><th class="num">Cant.</th><th class="num">Precio unit.</th><th class="num">Total</th></tr>
+</thead>
+<tbody>
+{{range .Total.Lines}}<tr>
+<td>{{.Description}}{{with .Note}}<div class="note">{{.}}</div>{{end}}</td>
+<td class="num">{{.Quantity}}</td>
+<td class="num">{{with .UnitPrice}}{{.}}{{end}}</td>
+<td class="num">{{.Amount}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+
+<table>
+<tr><td>Subtotal</td><td class="num">{{.Total.Subtotal}}</td></tr>
+{{range .Total.Discounts}}<tr><td>{{.Description}}</td><td class="num">{{negative .Amount}}</td></tr>
+{{end}}{{range .Total.Taxes}}<tr><td>{{taxLabel .}} (base {{.Base}})</td><td class="num">{{.Tax}}</td></tr>
+{{end}}<tr class="total"><td>Total</td><td class="num">{{.Total.Total}}</td></tr>
+{{range .Total.Payments}}<tr><td>Pago: {{.Description}}</td><td class="num">{{negative .Amount}}</td></tr>
+{{end}}<tr class="total"><td>Saldo pendiente</td><td class="num">{{.Total.Due}}</td></tr>
+</table>
+</body>
+</html>
+`))
+
+// RenderHTML escribe el recibo como pagina HTML
+func RenderHTML(w io.Writer, d *Document) error {
+	return htmlTemplate.Execute(w, d)
+}
@@ -0,0 +1,252 @@
+package receipt
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Pagina A4 en puntos
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+	margin     = 50.0
+)
+
+// Fuentes estandar de PDF, no requieren incrustarse
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+	fontMono    = "F3"
+)
+
+var pdfFonts = []struct{ name, base string }{
+	{fontRegular, "Helvetica"},
+	{fontBold, "Helvetica-Bold"},
+	{fontMono, "Courier"},
+}
+
+// pdfWriter arma un PDF minimo de texto y lineas, con las fuentes estandar y
+// saltos de pagina automaticos
+type pdfWriter struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64
+}
+
+func newPDFWriter() *pdfWriter {
+	p := &pdfWriter{}
+	p.newPage()
+	return p
+}
+
+func (p *pdfWriter) newPage() {
+	p.page = &bytes.Buffer{}
+	p.pages = append(p.pages, p.page)
+	p.y = pageHeight - margin
+}
+
+// advance baja el cursor una linea, pasando de pagina si no hay lugar
+func (p *pdfWriter) advance(height float64) {
+	if p.y-height < margin {
+		p.newPage()
+	}
+	p.y -= height
+}
+
+func (p *pdfWriter) text(x float64, font string, size float64, s string) {
+	fmt.Fprintf(p.page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, p.y, pdfString(s))
+}
+
+// textRight alinea el texto a la derecha de x. Usa Courier, donde cada
+// caracter mide 0.6 del tamano de la fuente.
+func (p *pdfWriter) textRight(x float64, size float64, s string) {
+	width := float64(len([]rune(s))) * 0.6 * size
+	p.text(x-width, fontMono, size, s)
+}
+
+func (p *pdfWriter) rule() {
+	fmt.Fprintf(p.page, "0.5 w %.2f %.2f m %.2f %.2f l S\n", margin, p.y, pageWidth-margin, p.y)
+}
+
+// WriteTo escribe el documento: catalogo, arbol de paginas, fuentes y una
+// pagina con su contenido comprimido por cada pagina armada
+func (p *pdfWriter) WriteTo(w io.Writer) (int64, error) {
+	var out bytes.Buffer
+	var offsets []int
+
+	object := func(body string) int {
+		offsets = append(offsets, out.Len())
+		id := len(offsets)
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", id, body)
+		return id
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Los objetos 1 y 2 son el catalogo y el arbol de paginas
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	pagesOffset := len(offsets)
+	offsets = append(offsets, 0)
+
+	var fonts strings.Builder
+	for _, f := range pdfFonts {
+		id := object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", f.base))
+		fmt.Fprintf(&fonts, "/%s %d 0 R ", f.name, id)
+	}
+
+	var kids []string
+	for _, page := range p.pages {
+		var content bytes.Buffer
+		zw := zlib.NewWriter(&content)
+		if _, err := zw.Write(page.Bytes()); err != nil {
+			return 0, err
+		}
+		if err := zw.Close(); err != nil {
+			return 0, err
+		}
+
+		contentID := object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", content.Len(), content.Bytes()))
+		pageID := object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << %s>> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, fonts.String(), contentID))
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageID))
+	}
+
+	offsets[pagesOffset] = out.Len()
+	fmt.Fprintf(&out, "2 0 obj\n<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", strings.Join(kids, " "), len(kids))
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	n, err := w.Write(out.Bytes())
+	return int64(n), err
+}
+
+// pdfString convierte el texto a WinAnsi y escapa los caracteres especiales
+// de las cadenas PDF. Lo que no existe en WinAnsi se reemplaza por '?'.
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '€':
+			b.WriteString(`\200`)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// truncate corta el texto para que no invada las columnas de importes
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-3]) + "..."
+}
+
+// RenderPDF escribe el recibo como documento PDF
+func RenderPDF(w io.Writer, d *Document) error {
+	p := newPDFWriter()
+	right := pageWidth - margin
+
+	// Encabezado con los datos de la barberia
+	p.advance(18)
+	p.text(margin, fontBold, 16, d.Shop.Name)
+	for _, line := range []string{prefixed("NIT ", d.Shop.TaxID), d.Shop.Address, prefixed("Tel. ", d.Shop.Phone)} {
+		if line != "" {
+			p.advance(14)
+			p.text(margin, fontRegular, 10, line)
+		}
+	}
+
+	p.advance(26)
+	p.text(margin, fontBold, 12, "Recibo No. "+d.Number)
+	p.advance(14)
+	p.text(margin, fontRegular, 10, "Fecha de emision: "+formatDate(d.IssuedAt))
+	p.advance(14)
+	p.text(margin, fontRegular, 10, "Cliente: "+d.ClientName)
+	if d.BarberName != "" {
+		p.advance(14)
+		p.text(margin, fontRegular, 10, "Barbero: "+d.BarberName)
+	}
+	p.advance(14)
+	p.text(margin, fontRegular, 10, "Cita: "+formatDate(d.AppointmentStart))
+
+	// Lineas de la cita
+	p.advance(26)
+	p.text(margin, fontBold, 10, "Descripcion")
+	p.text(300, fontBold, 10, "Cant.")
+	p.text(380, fontBold, 10, "Precio unit.")
+	p.text(right-30, fontBold, 10, "Total")
+	p.advance(6)
+	p.rule()
+
+	for _, line := range d.Total.Lines {
+		p.advance(16)
+		p.text(margin, fontRegular, 10, truncate(line.Description, 40))
+		p.textRight(330, 9, fmt.Sprint(line.Quantity))
+		if line.UnitPrice != nil {
+			p.textRight(460, 9, line.UnitPrice.String())
+		}
+		p.textRight(right, 9, line.Amount.String())
+		if line.Note != "" {
+			p.advance(12)
+			p.text(margin+10, fontRegular, 8, truncate(line.Note, 70))
+		}
+	}
+
+	// Totales, impuestos y pagos. La base de cada impuesto va en la columna
+	// de precios para que no compita con la descripcion.
+	p.advance(10)
+	p.rule()
+	row := func(font, label, base, amount string) {
+		p.advance(16)
+		p.text(margin, font, 10, truncate(label, 55))
+		if base != "" {
+			p.textRight(460, 9, base)
+		}
+		p.textRight(right, 9, amount)
+	}
+
+	row(fontRegular, "Subtotal", "", d.Total.Subtotal.String())
+	for _, line := range d.Total.Discounts {
+		row(fontRegular, line.Description, "", negative(line.Amount).String())
+	}
+	if len(d.Total.Taxes) > 0 {
+		p.advance(14)
+		p.text(440, fontBold, 8, "Base")
+	}
+	for _, line := range d.Total.Taxes {
+		row(fontRegular, taxLabel(line), line.Base.String(), line.Tax.String())
+	}
+	row(fontBold, "Total", "", d.Total.Total.String())
+	for _, line := range d.Total.Payments {
+		row(fontRegular, "Pago: "+line.Description, "", negative(line.Amount).String())
+	}
+	row(fontBold, "Saldo pendiente", "", d.Total.Due.String())
+
+	_, err := p.WriteTo(w)
+	return err
+}
+
+func prefixed(prefix, value string) string {
+	if value == "" {
+		return ""
+	}
+	return prefix + value
+}
@@ -0,0 +1,64 @@
+// Package receipt genera los recibos de las citas en HTML y PDF, sin
+// dependencias externas.
+package receipt
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+)
+
+// Shop son los datos de la barberia que encabezan el recibo
+type Shop struct {
+	Name    string
+	TaxID   string
+	Address string
+	Phone   string
+}
+
+// Document reune lo que se imprime en el recibo: el numero emitido, los datos
+// de la cita y el detalle del total con impuestos y pagos
+type Document struct {
+	Shop             Shop
+	Number           string
+	IssuedAt         time.Time
+	ClientName       string
+	BarberName       string
+	AppointmentStart time.Time
+	Total            *domain.AppointmentTotal
+}
+
+// FormatNumber arma el numero visible del recibo, ej: R-000042
+func FormatNumber(prefix string, number int64) string {
+	return fmt.Sprintf("%s%06d", prefix, number)
+}
+
+// Filename es el nombre del archivo del recibo con la extension indicada
+func (d *Document) Filename(ext string) string {
+	return "recibo-" + d.Number + "." + ext
+}
+
+// taxLabel describe la tarifa de una linea de impuestos, ej: IVA 19%
+func taxLabel(line domain.TaxLine) string {
+	if line.Exempt {
+		return "Exento"
+	}
+	return "IVA " + formatRate(line.Rate)
+}
+
+// formatRate muestra una tarifa en puntos basicos como porcentaje
+func formatRate(bps int) string {
+	if bps%100 == 0 {
+		return fmt.Sprintf("%d%%", bps/100)
+	}
+	return fmt.Sprintf("%d.%02d%%", bps/100, bps%100)
+}
+
+func formatDate(t time.Time) string {
+	return t.Format("02/01/2006 15:04")
+}
+
+func negative(m domain.Money) domain.Money {
+	return domain.NewMoney(-m.Cents, m.Currency)
+}
@@ -0,0 +1,375 @@
+package receipt
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+)
+
+func cop(cents int64) domain.Money {
+	return domain.NewMoney(cents, "COP")
+}
+
+func sampleDocument() *Document {
+	unit := cop(10000)
+	return &Document{
+		Shop:             Shop{Name: "Barberia Central", TaxID: "900123456-7", Address: "Calle 10 # 5-20", Phone: "3001234567"},
+		Number:           FormatNumber("R-", 42),
+		IssuedAt:         time.Date(2026, 3, 14, 18, 30, 0, 0, time.UTC),
+		ClientName:       "Juan Perez",
+		BarberName:       "Carlos",
+		AppointmentStart: time.Date(2026, 3, 14, 17, 0, 0, 0, time.UTC),
+		Total: &domain.AppointmentTotal{
+			Lines: []domain.TotalLine{
+				{Kind: "product", Description: "Corte clasico", Quantity: 1, UnitPrice: &unit, Amount: cop(10000)},
+			},
+			Subtotal:  cop(10000),
+			Discounts: []domain.TotalLine{{Kind: "promotion", Description: "Promocion BIENVENIDA", Amount: cop(1000)}},
+			Taxes:     []domain.TaxLine{{Rate: 1900, Base: cop(9000), Tax: cop(1710)}},
+			TaxTotal:  cop(1710),
+			Total:     cop(10710),
+			Payments:  []domain.TotalLine{{Kind: "voucher", Description: "Tarjeta de regalo", Amount: cop(5000)}},
+			Due:       cop(5710),
+		},
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		edit    func(d *Document)
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "encabezado y totales",
+			want: []string{
+				"<title>Recibo R-000042</title>",
+				"<h1>Barberia Central</h1>",
+				"<div>NIT 900123456-7</div>",
+				"<div>Fecha de emision: 14/03/2026 18:30</div>",
+				"<div>Barbero: Carlos</div>",
+				"<div>Cita: 14/03/2026 17:00</div>",
+				`<td class="num">100.00 COP</td>`,
+				`<tr><td>Subtotal</td><td class="num">100.00 COP</td></tr>`,
+				`<tr><td>Promocion BIENVENIDA</td><td class="num">-10.00 COP</td></tr>`,
+				`<tr><td>IVA 19% (base 90.00 COP)</td><td class="num">17.10 COP</td></tr>`,
+				`<tr class="total"><td>Total</td><td class="num">107.10 COP</td></tr>`,
+				`<tr><td>Pago: Tarjeta de regalo</td><td class="num">-50.00 COP</td></tr>`,
+				`<tr class="total"><td>Saldo pendiente</td><td class="num">57.10 COP</td></tr>`,
+			},
+		},
+		{
+			name: "datos opcionales vacios",
+			edit: func(d *Document) {
+				d.Shop.TaxID = ""
+				d.Shop.Phone = ""
+				d.BarberName = ""
+				d.Total.Payments = nil
+			},
+			notWant: []string{"NIT", "Tel.", "Barbero:", "Pago:"},
+		},
+		{
+			name: "escapa el texto de la cita",
+			edit: func(d *Document) {
+				d.ClientName = `<script>alert("x")</script>`
+				d.Total.Lines[0].Description = "Corte & barba"
+				d.Total.Lines[0].Note = "<b>precio ajustado</b>"
+			},
+			want: []string{
+				"Cliente: &lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;",
+				"Corte &amp; barba",
+				`<div class="note">&lt;b&gt;precio ajustado&lt;/b&gt;</div>`,
+			},
+			notWant: []string{"<script>", "<b>"},
+		},
+	}
+
+	for _, tt := range tests {
+		d := sampleDocument()
+		if tt.edit != nil {
+			tt.edit(d)
+		}
+
+		var buf bytes.Buffer
+		if err := RenderHTML(&buf, d); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		out := buf.String()
+
+		for _, want := range tt.want {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s: falta %q", tt.name, want)
+			}
+		}
+		for _, notWant := range tt.notWant {
+			if strings.Contains(out, notWant) {
+				t.Errorf("%s: no se esperaba %q", tt.name, notWant)
+			}
+		}
+	}
+}
+
+// pdfText es un texto dibujado en una pagina del PDF
+type pdfText struct {
+	page int
+	font string
+	size float64
+	x, y float64
+	text string
+}
+
+// right es el borde derecho del texto. Solo es exacto en Courier, la fuente
+// de los importes.
+func (t pdfText) right() float64 {
+	return t.x + float64(len(unescapePDF(t.text)))*0.6*t.size
+}
+
+var textOp = regexp.MustCompile(`BT /(\w+) ([\d.]+) Tf ([\d.-]+) ([\d.-]+) Td \((.*)\) Tj ET`)
+
+// pdfTexts descomprime las paginas del PDF y devuelve sus textos en orden
+func pdfTexts(t *testing.T, pdf []byte) []pdfText {
+	t.Helper()
+
+	var texts []pdfText
+	rest := pdf
+	for page := 0; ; page++ {
+		start := bytes.Index(rest, []byte("stream\n"))
+		if start < 0 {
+			break
+		}
+		rest = rest[start+len("stream\n"):]
+		end := bytes.Index(rest, []byte("\nendstream"))
+		if end < 0 {
+			t.Fatal("stream sin cerrar")
+		}
+
+		zr, err := zlib.NewReader(bytes.NewReader(rest[:end]))
+		if err != nil {
+			t.Fatalf("descomprimiendo la pagina %d: %v", page, err)
+		}
+		content, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatalf("descomprimiendo la pagina %d: %v", page, err)
+		}
+		rest = rest[end+len("\nendstream"):]
+
+		for _, m := range textOp.FindAllStringSubmatch(string(content), -1) {
+			size, _ := strconv.ParseFloat(m[2], 64)
+			x, _ := strconv.ParseFloat(m[3], 64)
+			y, _ := strconv.ParseFloat(m[4], 64)
+			texts = append(texts, pdfText{page: page, font: m[1], size: size, x: x, y: y, text: m[5]})
+		}
+	}
+
+	return texts
+}
+
+// unescapePDF deshace los escapes de pdfString para medir el texto
+func unescapePDF(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 >= len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+3 < len(s) && s[i+1] >= '0' && s[i+1] <= '7' {
+			b.WriteByte('?')
+			i += 3
+			continue
+		}
+		b.WriteByte(s[i+1])
+		i++
+	}
+	return b.String()
+}
+
+// findText busca el texto a partir de la posicion from y devuelve su
+// posicion, o -1 si no esta
+func findText(texts []pdfText, from int, s string) int {
+	for i := from; i < len(texts); i++ {
+		if texts[i].text == s {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestRenderPDFLayout(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderPDF(&buf, sampleDocument()); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.Bytes()
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatal("el documento no empieza ni termina como un PDF")
+	}
+
+	// startxref debe apuntar a la tabla de referencias
+	idx := bytes.LastIndex(out, []byte("startxref\n"))
+	offset, err := strconv.Atoi(strings.Fields(string(out[idx+len("startxref\n"):]))[0])
+	if err != nil || !bytes.HasPrefix(out[offset:], []byte("xref\n")) {
+		t.Errorf("startxref %d no apunta a la tabla xref", offset)
+	}
+
+	texts := pdfTexts(t, out)
+	amountsRight := pageWidth - margin
+
+	tests := []struct {
+		text  string
+		font  string
+		x     float64 // borde izquierdo, si right es cero
+		right float64 // borde derecho de los importes
+	}{
+		{text: "Barberia Central", font: fontBold, x: margin},
+		{text: "NIT 900123456-7", font: fontRegular, x: margin},
+		{text: "Recibo No. R-000042", font: fontBold, x: margin},
+		{text: "Cliente: Juan Perez", font: fontRegular, x: margin},
+		{text: "Corte clasico", font: fontRegular, x: margin},
+		{text: "1", font: fontMono, right: 330},
+		{text: "100.00 COP", font: fontMono, right: 460},
+		{text: "100.00 COP", font: fontMono, right: amountsRight},
+		{text: "Subtotal", font: fontRegular, x: margin},
+		{text: "Promocion BIENVENIDA", font: fontRegular, x: margin},
+		{text: "-10.00 COP", font: fontMono, right: amountsRight},
+		{text: "Base", font: fontBold, x: 440},
+		{text: "IVA 19%", font: fontRegular, x: margin},
+		{text: "90.00 COP", font: fontMono, right: 460},
+		{text: "17.10 COP", font: fontMono, right: amountsRight},
+		{text: "Total", font: fontBold, x: margin},
+		{text: "107.10 COP", font: fontMono, right: amountsRight},
+		{text: "Pago: Tarjeta de regalo", font: fontRegular, x: margin},
+		{text: "-50.00 COP", font: fontMono, right: amountsRight},
+		{text: "Saldo pendiente", font: fontBold, x: margin},
+		{text: "57.10 COP", font: fontMono, right: amountsRight},
+	}
+
+	// Los textos se buscan en el orden en que se dibujan
+	from, prevY := 0, pageHeight
+	for _, tt := range tests {
+		i := findText(texts, from, tt.text)
+		if i < 0 {
+			t.Errorf("falta el texto %q despues de %q", tt.text, texts[from].text)
+			continue
+		}
+		got := texts[i]
+		from = i + 1
+
+		if got.font != tt.font {
+			t.Errorf("%q: fuente %s, se esperaba %s", tt.text, got.font, tt.font)
+		}
+		if tt.right != 0 && math.Abs(got.right()-tt.right) > 0.01 {
+			t.Errorf("%q: termina en x=%.2f, se esperaba %.2f", tt.text, got.right(), tt.right)
+		}
+		if tt.right == 0 && got.x != tt.x {
+			t.Errorf("%q: empieza en x=%.2f, se esperaba %.2f", tt.text, got.x, tt.x)
+		}
+
+		// Las filas se dibujan de arriba hacia abajo en el orden de la tabla
+		if got.y > prevY {
+			t.Errorf("%q: esta en y=%.2f, por encima de la fila anterior (%.2f)", tt.text, got.y, prevY)
+		}
+		prevY = got.y
+	}
+
+	// La base del impuesto va en la misma fila que su tarifa
+	label := texts[findText(texts, 0, "IVA 19%")]
+	base := texts[findText(texts, 0, "90.00 COP")]
+	if label.y != base.y {
+		t.Errorf("la base del impuesto esta en y=%.2f y la tarifa en y=%.2f", base.y, label.y)
+	}
+}
+
+func TestRenderPDFPageBreak(t *testing.T) {
+	d := sampleDocument()
+	unit := cop(1000)
+	for range 60 {
+		d.Total.Lines = append(d.Total.Lines, domain.TotalLine{Description: "Producto", Quantity: 1, UnitPrice: &unit, Amount: unit})
+	}
+
+	var buf bytes.Buffer
+	if err := RenderPDF(&buf, d); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Contains(buf.Bytes(), []byte("/Count 2 >>")) {
+		t.Error("se esperaban dos paginas")
+	}
+
+	texts := pdfTexts(t, buf.Bytes())
+	for _, text := range texts {
+		if text.y < margin || text.y > pageHeight-margin {
+			t.Errorf("%q: y=%.2f queda fuera de los margenes", text.text, text.y)
+		}
+	}
+
+	if i := findText(texts, 0, "Saldo pendiente"); i < 0 || texts[i].page != 1 {
+		t.Error("el saldo pendiente deberia estar en la segunda pagina")
+	}
+}
+
+func TestPDFString(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Corte clasico", "Corte clasico"},
+		{"Perez (VIP)", `Perez \(VIP\)`},
+		{`C:\recibos`, `C:\\recibos`},
+		{"Peña", `Pe\361a`},
+		{"10 €", `10 \200`},
+		{"Corte ✂", "Corte ?"},
+		{"linea\nnueva", "linea?nueva"},
+	}
+
+	for _, tt := range tests {
+		if got := pdfString(tt.in); got != tt.want {
+			t.Errorf("pdfString(%q) = %q, se esperaba %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in    string
+		limit int
+		want  string
+	}{
+		{"Corte", 10, "Corte"},
+		{"Corte y barba", 13, "Corte y barba"},
+		{"Corte y barba", 10, "Corte y..."},
+		{"Ñañaña ñañaña", 8, "Ñañañ..."},
+	}
+
+	for _, tt := range tests {
+		if got := truncate(tt.in, tt.limit); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, se esperaba %q", tt.in, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestTaxLabel(t *testing.T) {
+	tests := []struct {
+		line domain.TaxLine
+		want string
+	}{
+		{domain.TaxLine{Rate: 1900}, "IVA 19%"},
+		{domain.TaxLine{Rate: 550}, "IVA 5.50%"},
+		{domain.TaxLine{Rate: 0}, "IVA 0%"},
+		{domain.TaxLine{Exempt: true}, "Exento"},
+	}
+
+	for _, tt := range tests {
+		if got := taxLabel(tt.line); got != tt.want {
+			t.Errorf("taxLabel(%+v) = %q, se esperaba %q", tt.line, got, tt.want)
+		}
+	}
+}
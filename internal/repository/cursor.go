@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+)
+
+// pageCursor identifica el ultimo registro devuelto: el valor de la columna
+// de orden y el id para desempatar
+type pageCursor struct {
+	Value string `json:"v"`
+	ID    uint   `json:"id"`
+}
+
+func encodeCursor(value string, id uint) string {
+	data, _ := json.Marshal(pageCursor{value, id})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(cursor string) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cursor invalido", domain.ErrorInvalidInput)
+	}
+
+	var c pageCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%w: cursor invalido", domain.ErrorInvalidInput)
+	}
+
+	return &c, nil
+}
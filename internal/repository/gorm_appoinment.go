@@ -2,11 +2,20 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/alexnt4/barber-api/internal/domain"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// exclusionViolation es el codigo de Postgres cuando se viola la restriccion
+// que impide turnos solapados del mismo barbero
+const exclusionViolation = "23P01"
+
 type GormAppoinmentRepo struct {
 	db *gorm.DB
 }
@@ -16,13 +25,13 @@ func NewGormAppoinmentRepo(db *gorm.DB) domain.AppointmentRepo {
 }
 
 func (r *GormAppoinmentRepo) Create(ctx context.Context, appt *domain.Appointment) error {
-	return r.db.WithContext(ctx).Create(appt).Error
+	return translateOverlap(conn(ctx, r.db).Create(appt).Error)
 }
 
 func (r *GormAppoinmentRepo) GetById(ctx context.Context, id uint) (*domain.Appointment, error) {
 	var appt domain.Appointment
 
-	if err := r.db.WithContext(ctx).Preload("Products").First(&appt, id).Error; err != nil {
+	if err := conn(ctx, r.db).Preload("Items", orderItems).Preload("Barber").Preload("Client").First(&appt, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domain.ErrorNotFound
 		}
@@ -32,20 +41,182 @@ func (r *GormAppoinmentRepo) GetById(ctx context.Context, id uint) (*domain.Appo
 	return &appt, nil
 }
 
-func (r *GormAppoinmentRepo) List(ctx context.Context) ([]domain.Appointment, error) {
+func (r *GormAppoinmentRepo) List(ctx context.Context, opts domain.AppointmentListOptions) ([]domain.Appointment, string, error) {
+	var appts []domain.Appointment
+
+	query := conn(ctx, r.db).Preload("Items", orderItems).Preload("Barber").Preload("Client")
+
+	// Filtros
+	if opts.From != nil {
+		query = query.Where("start_time >= ?", *opts.From)
+	}
+	if opts.To != nil {
+		query = query.Where("start_time < ?", *opts.To)
+	}
+	if opts.BarberID != 0 {
+		query = query.Where("barber_id = ?", opts.BarberID)
+	}
+	if opts.ClientID != 0 {
+		query = query.Where("client_id = ?", opts.ClientID)
+	}
+	if opts.Status != "" {
+		query = query.Where("status = ?", opts.Status)
+	}
+	if opts.ProductID != 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM appointment_items ai WHERE ai.appointment_id = appointments.id AND ai.product_id = ?)", opts.ProductID)
+	}
+
+	// Orden y paginacion por cursor sobre (start_time, id)
+	direction, comparison := "ASC", ">"
+	if opts.SortDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if opts.Cursor != "" {
+		cursor, err := decodeCursor(opts.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+
+		after, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: cursor invalido", domain.ErrorInvalidInput)
+		}
+
+		query = query.Where("(start_time, id) "+comparison+" (?, ?)", after, cursor.ID)
+	}
+
+	query = query.Order("start_time " + direction).Order("id " + direction)
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit + 1)
+	}
+
+	if err := query.Find(&appts).Error; err != nil {
+		return nil, "", err
+	}
+
+	// Si hay un registro de mas, existe una pagina siguiente
+	var nextCursor string
+	if opts.Limit > 0 && len(appts) > opts.Limit {
+		appts = appts[:opts.Limit]
+		last := appts[len(appts)-1]
+		nextCursor = encodeCursor(last.StartTime.Format(time.RFC3339Nano), last.ID)
+	}
+
+	return appts, nextCursor, nil
+}
+
+func (r *GormAppoinmentRepo) ListBySeries(ctx context.Context, seriesID uint) ([]domain.Appointment, error) {
+	var appts []domain.Appointment
+
+	if err := conn(ctx, r.db).Preload("Items", orderItems).Preload("Barber").Preload("Client").
+		Where("series_id = ?", seriesID).Order("start_time").Find(&appts).Error; err != nil {
+		return nil, err
+	}
+
+	return appts, nil
+}
+
+// FindOverlapping devuelve las citas activas del barbero que se cruzan con
+// el rango [start, end), sin cargar sus productos
+func (r *GormAppoinmentRepo) FindOverlapping(ctx context.Context, barberID uint, start, end time.Time, excludeID uint) ([]domain.Appointment, error) {
 	var appts []domain.Appointment
 
-	if err := r.db.WithContext(ctx).Preload("Products").Find(&appts).Error; err != nil {
+	query := conn(ctx, r.db).
+		Where("barber_id = ? AND start_time < ? AND end_time > ?", barberID, end, start).
+		Where("status <> ?", domain.StatusCancelled)
+	if excludeID != 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+
+	if err := query.Order("start_time").Find(&appts).Error; err != nil {
 		return nil, err
 	}
 
 	return appts, nil
 }
 
+// CountByClientStatus cuenta las citas del cliente en el estado indicado que
+// empiezan a partir de since
+func (r *GormAppoinmentRepo) CountByClientStatus(ctx context.Context, clientID uint, status domain.AppointmentStatus, since time.Time) (int64, error) {
+	var count int64
+
+	err := conn(ctx, r.db).Model(&domain.Appointment{}).
+		Where("client_id = ? AND status = ? AND start_time >= ?", clientID, status, since).
+		Count(&count).Error
+
+	return count, err
+}
+
+// Update guarda la cita con sus lineas, eliminando las que ya no tiene
 func (r *GormAppoinmentRepo) Update(ctx context.Context, appt *domain.Appointment) error {
-	return r.db.WithContext(ctx).Session(&gorm.Session{FullSaveAssociations: true}).Save(appt).Error
+	return translateOverlap(conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		return saveAppointment(tx, appt)
+	}))
+}
+
+// UpdateWithFee guarda la cita y registra el cargo juntos, para que no quede
+// una cita cancelada sin su cargo ni un cargo sin la cancelacion
+func (r *GormAppoinmentRepo) UpdateWithFee(ctx context.Context, appt *domain.Appointment, fee *domain.Fee) error {
+	return translateOverlap(conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := saveAppointment(tx, appt); err != nil {
+			return err
+		}
+
+		if fee == nil {
+			return nil
+		}
+		return tx.Create(fee).Error
+	}))
+}
+
+// saveAppointment guarda la cita con sus lineas dentro de la transaccion
+func saveAppointment(tx *gorm.DB, appt *domain.Appointment) error {
+	keep := []uint{0}
+	for _, item := range appt.Items {
+		if item.ID != 0 {
+			keep = append(keep, item.ID)
+		}
+	}
+
+	if err := tx.Where("appointment_id = ? AND id NOT IN ?", appt.ID, keep).
+		Delete(&domain.AppointmentItem{}).Error; err != nil {
+		return err
+	}
+
+	// El barbero y el cliente se editan desde sus propios endpoints
+	return tx.Session(&gorm.Session{FullSaveAssociations: true}).
+		Omit("Barber", "Client").Save(appt).Error
+}
+
+// lockAppointment bloquea la fila de la cita hasta el fin de la transaccion
+func lockAppointment(tx *gorm.DB, apptID uint) error {
+	var appt domain.Appointment
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").First(&appt, apptID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return domain.ErrorNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// orderItems carga las lineas de la cita en el orden en que se agregaron
+func orderItems(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
 }
 
 func (r *GormAppoinmentRepo) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&domain.Appointment{}, id).Error
+	return conn(ctx, r.db).Delete(&domain.Appointment{}, id).Error
+}
+
+func translateOverlap(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == exclusionViolation {
+		return domain.ErrorOverlap
+	}
+
+	return err
 }
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"gorm.io/gorm"
+)
+
+type GormAppointmentSeriesRepo struct {
+	db *gorm.DB
+}
+
+func NewGormAppointmentSeriesRepo(db *gorm.DB) domain.AppointmentSeriesRepo {
+	return &GormAppointmentSeriesRepo{db}
+}
+
+func (r *GormAppointmentSeriesRepo) Create(ctx context.Context, series *domain.AppointmentSeries) error {
+	return conn(ctx, r.db).Create(series).Error
+}
+
+func (r *GormAppointmentSeriesRepo) GetById(ctx context.Context, id uint) (*domain.AppointmentSeries, error) {
+	var series domain.AppointmentSeries
+
+	if err := conn(ctx, r.db).First(&series, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrorNotFound
+		}
+		return nil, err
+	}
+
+	return &series, nil
+}
+
+func (r *GormAppointmentSeriesRepo) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&domain.AppointmentSeries{}, id).Error
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"gorm.io/gorm"
+)
+
+type GormBarberRepo struct {
+	db *gorm.DB
+}
+
+func NewGormBarberRepo(db *gorm.DB) domain.BarberRepo {
+	return &GormBarberRepo{db}
+}
+
+func (r *GormBarberRepo) Create(ctx context.Context, barber *domain.Barber) error {
+	return conn(ctx, r.db).Create(barber).Error
+}
+
+func (r *GormBarberRepo) GetById(ctx context.Context, id uint) (*domain.Barber, error) {
+	var barber domain.Barber
+
+	if err := conn(ctx, r.db).First(&barber, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrorNotFound
+		}
+		return nil, err
+	}
+
+	return &barber, nil
+}
+
+func (r *GormBarberRepo) List(ctx context.Context) ([]domain.Barber, error) {
+	var barbers []domain.Barber
+
+	if err := conn(ctx, r.db).Order("id").Find(&barbers).Error; err != nil {
+		return nil, err
+	}
+
+	return barbers, nil
+}
+
+func (r *GormBarberRepo) Update(ctx context.Context, barber *domain.Barber) error {
+	return conn(ctx, r.db).Save(barber).Error
+}
+
+func (r *GormBarberRepo) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&domain.Barber{}, id).Error
+}
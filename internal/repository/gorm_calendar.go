@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"gorm.io/gorm"
+)
+
+type GormCalendarRepo struct {
+	db *gorm.DB
+}
+
+func NewGormCalendarRepo(db *gorm.DB) domain.CalendarRepo {
+	return &GormCalendarRepo{db}
+}
+
+func (r *GormCalendarRepo) ListWeeklyHours(ctx context.Context) ([]domain.BusinessHours, error) {
+	var hours []domain.BusinessHours
+
+	if err := conn(ctx, r.db).Order("weekday").Find(&hours).Error; err != nil {
+		return nil, err
+	}
+
+	return hours, nil
+}
+
+func (r *GormCalendarRepo) GetWeeklyHours(ctx context.Context, weekday int) (*domain.BusinessHours, error) {
+	var hours domain.BusinessHours
+
+	if err := conn(ctx, r.db).First(&hours, "weekday = ?", weekday).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrorNotFound
+		}
+		return nil, err
+	}
+
+	return &hours, nil
+}
+
+func (r *GormCalendarRepo) SaveWeeklyHours(ctx context.Context, hours *domain.BusinessHours) error {
+	return conn(ctx, r.db).Save(hours).Error
+}
+
+func (r *GormCalendarRepo) CreateException(ctx context.Context, exc *domain.CalendarException) error {
+	return conn(ctx, r.db).Create(exc).Error
+}
+
+func (r *GormCalendarRepo) GetExceptionById(ctx context.Context, id uint) (*domain.CalendarException, error) {
+	var exc domain.CalendarException
+
+	if err := conn(ctx, r.db).First(&exc, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrorNotFound
+		}
+		return nil, err
+	}
+
+	return &exc, nil
+}
+
+func (r *GormCalendarRepo) GetExceptionByDate(ctx context.Context, date time.Time) (*domain.CalendarException, error) {
+	var exc domain.CalendarException
+
+	if err := conn(ctx, r.db).First(&exc, "date = ?", date.Format("2006-01-02")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrorNotFound
+		}
+		return nil, err
+	}
+
+	return &exc, nil
+}
+
+func (r *GormCalendarRepo) ListExceptions(ctx context.Context, from, to time.Time) ([]domain.CalendarException, error) {
+	var excs []domain.CalendarException
+
+	query := conn(ctx, r.db).Order("date")
+	if !from.IsZero() {
+		query = query.Where("date >= ?", from.Format("2006-01-02"))
+	}
+	if !to.IsZero() {
+		query = query.Where("date <= ?", to.Format("2006-01-02"))
+	}
+
+	if err := query.Find(&excs).Error; err != nil {
+		return nil, err
+	}
+
+	return excs, nil
+}
+
+func (r *GormCalendarRepo) UpdateException(ctx context.Context, exc *domain.CalendarException) error {
+	return conn(ctx, r.db).Save(exc).Error
+}
+
+func (r *GormCalendarRepo) DeleteException(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&domain.CalendarException{}, id).Error
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"gorm.io/gorm"
+)
+
+// clientTables son las tablas con registros que pertenecen a un cliente y
+// deben reasignarse al fusionar duplicados
+var clientTables = []string{"appointments", "waitlist_entries", "fees", "loyalty_entries", "promotion_redemptions"}
+
+// clientColumns agrega a los datos del cliente la cantidad de inasistencias
+const clientColumns = "clients.*, (SELECT COUNT(*) FROM appointments a WHERE a.client_id = clients.id AND a.status = 'no_show') AS no_show_count"
+
+type GormClientRepo struct {
+	db *gorm.DB
+}
+
+func NewGormClientRepo(db *gorm.DB) domain.ClientRepo {
+	return &GormClientRepo{db}
+}
+
+func (r *GormClientRepo) Create(ctx context.Context, client *domain.Client) error {
+	return conn(ctx, r.db).Create(client).Error
+}
+
+func (r *GormClientRepo) GetById(ctx context.Context, id uint) (*domain.Client, error) {
+	var client domain.Client
+
+	if err := conn(ctx, r.db).Select(clientColumns).First(&client, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrorNotFound
+		}
+		return nil, err
+	}
+
+	return &client, nil
+}
+
+// List devuelve los clientes cuyo nombre, telefono o email contienen search
+func (r *GormClientRepo) List(ctx context.Context, search string) ([]domain.Client, error) {
+	var clients []domain.Client
+
+	query := conn(ctx, r.db).Select(clientColumns)
+	if search != "" {
+		like := "%" + search + "%"
+		query = query.Where("(name ILIKE ? OR phone ILIKE ? OR email ILIKE ?)", like, like, like)
+	}
+
+	if err := query.Order("name, id").Find(&clients).Error; err != nil {
+		return nil, err
+	}
+
+	return clients, nil
+}
+
+func (r *GormClientRepo) Update(ctx context.Context, client *domain.Client) error {
+	return conn(ctx, r.db).Save(client).Error
+}
+
+func (r *GormClientRepo) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&domain.Client{}, id).Error
+}
+
+// Merge reasigna al sobreviviente todo lo que pertenece al duplicado, guarda
+// los datos combinados del sobreviviente y elimina el duplicado, todo en una
+// misma transaccion
+func (r *GormClientRepo) Merge(ctx context.Context, survivor *domain.Client, duplicateID uint) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for _, table := range clientTables {
+			if err := tx.Table(table).Where("client_id = ?", duplicateID).
+				Update("client_id", survivor.ID).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Save(survivor).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&domain.Client{}, duplicateID).Error
+	})
+}
+
+// Erase guarda los datos anonimizados del cliente, reemplaza su nombre en los
+// recibos emitidos y lo saca de la lista de espera. Las citas, los cargos y los
+// recibos se conservan para la contabilidad.
+func (r *GormClientRepo) Erase(ctx context.Context, client *domain.Client) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(client).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&domain.Receipt{}).
+			Where("appointment_id IN (?)", tx.Model(&domain.Appointment{}).Select("id").Where("client_id = ?", client.ID)).
+			Update("client_name", client.Name).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&domain.WaitlistEntry{}).
+			Where("client_id = ? AND status IN ?", client.ID, []domain.WaitlistStatus{domain.WaitlistWaiting, domain.WaitlistOffered}).
+			Updates(map[string]any{
+				"status":          domain.WaitlistCancelled,
+				"hold_expires_at": nil,
+			}).Error
+	})
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"gorm.io/gorm"
+)
+
+type GormFeeRepo struct {
+	db *gorm.DB
+}
+
+func NewGormFeeRepo(db *gorm.DB) domain.FeeRepo {
+	return &GormFeeRepo{db}
+}
+
+func (r *GormFeeRepo) Create(ctx context.Context, fee *domain.Fee) error {
+	return conn(ctx, r.db).Create(fee).Error
+}
+
+func (r *GormFeeRepo) ListByClient(ctx context.Context, clientID uint) ([]domain.Fee, error) {
+	var fees []domain.Fee
+
+	err := conn(ctx, r.db).
+		Where("client_id = ?", clientID).
+		Order("created_at DESC").
+		Find(&fees).Error
+
+	return fees, err
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormLoyaltyRepo struct {
+	db *gorm.DB
+}
+
+func NewGormLoyaltyRepo(db *gorm.DB) domain.LoyaltyRepo {
+	return &GormLoyaltyRepo{db}
+}
+
+func (r *GormLoyaltyRepo) ListByClient(ctx context.Context, clientID uint) ([]domain.LoyaltyEntry, error) {
+	var entries []domain.LoyaltyEntry
+
+	err := conn(ctx, r.db).
+		Where("client_id = ?", clientID).
+		Order("created_at DESC, id DESC").
+		Find(&entries).Error
+
+	return entries, err
+}
+
+func (r *GormLoyaltyRepo) ListByAppointment(ctx context.Context, apptID uint) ([]domain.LoyaltyEntry, error) {
+	var entries []domain.LoyaltyEntry
+
+	err := conn(ctx, r.db).
+		Where("appointment_id = ?", apptID).
+		Order("created_at, id").
+		Find(&entries).Error
+
+	return entries, err
+}
+
+func (r *GormLoyaltyRepo) Balance(ctx context.Context, clientID uint) (int, error) {
+	return loyaltyBalance(conn(ctx, r.db), clientID)
+}
+
+func (r *GormLoyaltyRepo) Create(ctx context.Context, entry *domain.LoyaltyEntry) error {
+	return conn(ctx, r.db).Create(entry).Error
+}
+
+// Redeem bloquea la cita y la fila del cliente para que dos canjes
+// simultaneos no puedan gastar el mismo saldo ni pagar de mas la cita
+func (r *GormLoyaltyRepo) Redeem(ctx context.Context, entry *domain.LoyaltyEntry, check domain.DueCheck) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if entry.AppointmentID != nil {
+			if err := lockAppointment(tx, *entry.AppointmentID); err != nil {
+				return err
+			}
+		}
+
+		// El pendiente se calcula dentro de la transaccion, con la cita bloqueada
+		if err := check(withTx(ctx, tx)); err != nil {
+			return err
+		}
+
+		var client domain.Client
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&client, entry.ClientID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return domain.ErrorNotFound
+			}
+			return err
+		}
+
+		available, err := loyaltyBalance(tx, entry.ClientID)
+		if err != nil {
+			return err
+		}
+
+		if available+entry.Points < 0 {
+			return domain.ErrorInsufficientFunds
+		}
+
+		return tx.Create(entry).Error
+	})
+}
+
+func loyaltyBalance(db *gorm.DB, clientID uint) (int, error) {
+	var total int
+
+	err := db.Model(&domain.LoyaltyEntry{}).
+		Select("COALESCE(SUM(points), 0)").
+		Where("client_id = ?", clientID).
+		Scan(&total).Error
+
+	return total, err
+}
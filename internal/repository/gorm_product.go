@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
 	"github.com/alexnt4/barber-api/internal/domain"
 	"gorm.io/gorm"
@@ -16,13 +18,13 @@ func NewGormProducttRepo(db *gorm.DB) domain.ProductRepo {
 }
 
 func (r *GormProductRepo) Create(ctx context.Context, prod *domain.Product) error {
-	return r.db.WithContext(ctx).Create(prod).Error
+	return conn(ctx, r.db).Create(prod).Error
 }
 
 func (r *GormProductRepo) GetById(ctx context.Context, id uint) (*domain.Product, error) {
 	var prod domain.Product
 
-	if err := r.db.WithContext(ctx).First(&prod, id).Error; err != nil {
+	if err := conn(ctx, r.db).First(&prod, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domain.ErrorNotFound
 		}
@@ -32,23 +34,81 @@ func (r *GormProductRepo) GetById(ctx context.Context, id uint) (*domain.Product
 	return &prod, nil
 }
 
-func (r *GormProductRepo) List(ctx context.Context) ([]domain.Product, error) {
+func (r *GormProductRepo) List(ctx context.Context, opts domain.ProductListOptions) ([]domain.Product, string, error) {
 	var prod []domain.Product
 
-	// if err := r.db.WithContext(ctx).Preload("Products").Find(&prod).Error; err != nil {
-	//	return nil, err
-	//}
-	if err := r.db.WithContext(ctx).Find(&prod).Error; err != nil {
-		return nil, err
+	query := conn(ctx, r.db)
+
+	// Filtros
+	if opts.Name != "" {
+		query = query.Where("name ILIKE ?", "%"+opts.Name+"%")
+	}
+	if opts.Category != "" {
+		query = query.Where("LOWER(category) = LOWER(?)", opts.Category)
+	}
+
+	// Orden y paginacion por cursor sobre (columna de orden, id)
+	column := "id"
+	switch opts.SortBy {
+	case "", "id":
+	case "name":
+		column = opts.SortBy
+	case "price":
+		column = "price_cents"
+	default:
+		return nil, "", fmt.Errorf("%w: orden no soportado %q", domain.ErrorInvalidInput, opts.SortBy)
+	}
+
+	direction, comparison := "ASC", ">"
+	if opts.SortDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if opts.Cursor != "" {
+		cursor, err := decodeCursor(opts.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if column == "id" {
+			query = query.Where("id "+comparison+" ?", cursor.ID)
+		} else {
+			query = query.Where("("+column+", id) "+comparison+" (?, ?)", cursor.Value, cursor.ID)
+		}
+	}
+
+	query = query.Order(column + " " + direction)
+	if column != "id" {
+		query = query.Order("id " + direction)
+	}
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit + 1)
+	}
+
+	if err := query.Find(&prod).Error; err != nil {
+		return nil, "", err
+	}
+
+	// Si hay un registro de mas, existe una pagina siguiente
+	var nextCursor string
+	if opts.Limit > 0 && len(prod) > opts.Limit {
+		prod = prod[:opts.Limit]
+		last := prod[len(prod)-1]
+
+		value := last.Name
+		if column == "price_cents" {
+			value = strconv.FormatInt(last.Price.Cents, 10)
+		}
+		nextCursor = encodeCursor(value, last.ID)
 	}
 
-	return prod, nil
+	return prod, nextCursor, nil
 }
 
 func (r *GormProductRepo) Update(ctx context.Context, prod *domain.Product) error {
-	return r.db.WithContext(ctx).Session(&gorm.Session{FullSaveAssociations: true}).Save(prod).Error
+	return conn(ctx, r.db).Session(&gorm.Session{FullSaveAssociations: true}).Save(prod).Error
 }
 
 func (r *GormProductRepo) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&domain.Product{}, id).Error
+	return conn(ctx, r.db).Delete(&domain.Product{}, id).Error
 }
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// promotionColumns agrega a la promocion la cantidad de usos vigentes
+const promotionColumns = "promotions.*, (SELECT COUNT(*) FROM promotion_redemptions pr WHERE pr.promotion_id = promotions.id AND pr.released_at IS NULL) AS uses"
+
+type GormPromotionRepo struct {
+	db *gorm.DB
+}
+
+func NewGormPromotionRepo(db *gorm.DB) domain.PromotionRepo {
+	return &GormPromotionRepo{db}
+}
+
+func (r *GormPromotionRepo) Create(ctx context.Context, promo *domain.Promotion) error {
+	err := conn(ctx, r.db).Create(promo).Error
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return domain.ErrorDuplicatePromo
+	}
+
+	return err
+}
+
+func (r *GormPromotionRepo) GetById(ctx context.Context, id uint) (*domain.Promotion, error) {
+	var promo domain.Promotion
+
+	if err := conn(ctx, r.db).Select(promotionColumns).First(&promo, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrorNotFound
+		}
+		return nil, err
+	}
+
+	return &promo, nil
+}
+
+func (r *GormPromotionRepo) GetByCode(ctx context.Context, code string) (*domain.Promotion, error) {
+	var promo domain.Promotion
+
+	if err := conn(ctx, r.db).Select(promotionColumns).Where("code = ?", code).First(&promo).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrorNotFound
+		}
+		return nil, err
+	}
+
+	return &promo, nil
+}
+
+func (r *GormPromotionRepo) List(ctx context.Context) ([]domain.Promotion, error) {
+	var promos []domain.Promotion
+
+	err := conn(ctx, r.db).Select(promotionColumns).Order("created_at DESC, id DESC").Find(&promos).Error
+
+	return promos, err
+}
+
+func (r *GormPromotionRepo) Update(ctx context.Context, promo *domain.Promotion) error {
+	return conn(ctx, r.db).Save(promo).Error
+}
+
+// Redeem bloquea la promocion mientras cuenta sus usos, para que dos reservas
+// simultaneas no superen el limite
+func (r *GormPromotionRepo) Redeem(ctx context.Context, redemption *domain.PromotionRedemption) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var promo domain.Promotion
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&promo, redemption.PromotionID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return domain.ErrorNotFound
+			}
+			return err
+		}
+
+		var applied int64
+		if err := tx.Model(&domain.PromotionRedemption{}).
+			Where("appointment_id = ? AND released_at IS NULL", redemption.AppointmentID).
+			Count(&applied).Error; err != nil {
+			return err
+		}
+
+		if applied > 0 {
+			return domain.ErrorPromotionApplied
+		}
+
+		if promo.MaxUses > 0 {
+			uses, err := promotionUses(tx, promo.ID, 0)
+			if err != nil {
+				return err
+			}
+			if uses >= int64(promo.MaxUses) {
+				return domain.ErrorPromotionLimit
+			}
+		}
+
+		if promo.MaxUsesPerClient > 0 {
+			uses, err := promotionUses(tx, promo.ID, redemption.ClientID)
+			if err != nil {
+				return err
+			}
+			if uses >= int64(promo.MaxUsesPerClient) {
+				return domain.ErrorPromotionLimit
+			}
+		}
+
+		return tx.Create(redemption).Error
+	})
+}
+
+func (r *GormPromotionRepo) CountUses(ctx context.Context, promoID, clientID uint) (int64, error) {
+	return promotionUses(conn(ctx, r.db), promoID, clientID)
+}
+
+func (r *GormPromotionRepo) GetRedemption(ctx context.Context, apptID uint) (*domain.PromotionRedemption, error) {
+	var redemption domain.PromotionRedemption
+
+	if err := conn(ctx, r.db).Preload("Promotion").
+		Where("appointment_id = ? AND released_at IS NULL", apptID).
+		First(&redemption).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrorNotFound
+		}
+		return nil, err
+	}
+
+	return &redemption, nil
+}
+
+// ListRedemptionsByClient devuelve todos los usos de promociones del
+// cliente, incluidos los liberados
+func (r *GormPromotionRepo) ListRedemptionsByClient(ctx context.Context, clientID uint) ([]domain.PromotionRedemption, error) {
+	var redemptions []domain.PromotionRedemption
+
+	err := conn(ctx, r.db).Preload("Promotion").
+		Where("client_id = ?", clientID).
+		Order("created_at DESC, id DESC").
+		Find(&redemptions).Error
+
+	return redemptions, err
+}
+
+func (r *GormPromotionRepo) Release(ctx context.Context, apptID uint, now time.Time) error {
+	return conn(ctx, r.db).Model(&domain.PromotionRedemption{}).
+		Where("appointment_id = ? AND released_at IS NULL", apptID).
+		Update("released_at", now).Error
+}
+
+// promotionUses cuenta los usos vigentes de la promocion, de todos los
+// clientes o solo del indicado
+func promotionUses(db *gorm.DB, promoID, clientID uint) (int64, error) {
+	var count int64
+
+	query := db.Model(&domain.PromotionRedemption{}).
+		Where("promotion_id = ? AND released_at IS NULL", promoID)
+	if clientID != 0 {
+		query = query.Where("client_id = ?", clientID)
+	}
+
+	err := query.Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"gorm.io/gorm"
+)
+
+type GormReceiptRepo struct {
+	db *gorm.DB
+}
+
+func NewGormReceiptRepo(db *gorm.DB) domain.ReceiptRepo {
+	return &GormReceiptRepo{db}
+}
+
+func (r *GormReceiptRepo) GetByAppointment(ctx context.Context, apptID uint) (*domain.Receipt, error) {
+	var receipt domain.Receipt
+
+	if err := conn(ctx, r.db).Where("appointment_id = ?", apptID).First(&receipt).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrorNotFound
+		}
+		return nil, err
+	}
+
+	return &receipt, nil
+}
+
+func (r *GormReceiptRepo) ListByClient(ctx context.Context, clientID uint) ([]domain.Receipt, error) {
+	var receipts []domain.Receipt
+
+	err := conn(ctx, r.db).
+		Joins("JOIN appointments a ON a.id = receipts.appointment_id").
+		Where("a.client_id = ?", clientID).
+		Order("receipts.number DESC").
+		Find(&receipts).Error
+
+	return receipts, err
+}
+
+// Issue bloquea la tabla de recibos mientras asigna el numero: una secuencia
+// de Postgres puede saltar numeros si la transaccion falla, y la numeracion
+// de los recibos no puede tener huecos. Las consultas siguen permitidas.
+func (r *GormReceiptRepo) Issue(ctx context.Context, receipt *domain.Receipt) (*domain.Receipt, error) {
+	var issued domain.Receipt
+
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("LOCK TABLE receipts IN EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+
+		// Otra solicitud pudo emitirlo mientras se esperaba el bloqueo
+		err := tx.Where("appointment_id = ?", receipt.AppointmentID).First(&issued).Error
+		if err == nil {
+			return nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		var last int64
+		if err := tx.Model(&domain.Receipt{}).Select("COALESCE(MAX(number), 0)").Scan(&last).Error; err != nil {
+			return err
+		}
+
+		issued = *receipt
+		issued.Number = last + 1
+		return tx.Create(&issued).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &issued, nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// uniqueViolation es el codigo de Postgres cuando se repite un valor unico
+const uniqueViolation = "23505"
+
+type GormVoucherRepo struct {
+	db *gorm.DB
+}
+
+func NewGormVoucherRepo(db *gorm.DB) domain.VoucherRepo {
+	return &GormVoucherRepo{db}
+}
+
+func (r *GormVoucherRepo) Create(ctx context.Context, voucher *domain.Voucher) error {
+	err := conn(ctx, r.db).Create(voucher).Error
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return domain.ErrorDuplicateCode
+	}
+
+	return err
+}
+
+func (r *GormVoucherRepo) GetByCode(ctx context.Context, code string) (*domain.Voucher, error) {
+	var voucher domain.Voucher
+
+	if err := conn(ctx, r.db).Where("code = ?", code).First(&voucher).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrorNotFound
+		}
+		return nil, err
+	}
+
+	return &voucher, nil
+}
+
+func (r *GormVoucherRepo) ListTransactions(ctx context.Context, apptID uint) ([]domain.VoucherTransaction, error) {
+	var txs []domain.VoucherTransaction
+
+	err := conn(ctx, r.db).Preload("Voucher").
+		Where("appointment_id = ?", apptID).
+		Order("created_at, id").
+		Find(&txs).Error
+
+	return txs, err
+}
+
+func (r *GormVoucherRepo) ListTransactionsByClient(ctx context.Context, clientID uint) ([]domain.VoucherTransaction, error) {
+	var txs []domain.VoucherTransaction
+
+	err := conn(ctx, r.db).Preload("Voucher").
+		Joins("JOIN appointments a ON a.id = voucher_transactions.appointment_id").
+		Where("a.client_id = ?", clientID).
+		Order("voucher_transactions.created_at DESC, voucher_transactions.id DESC").
+		Find(&txs).Error
+
+	return txs, err
+}
+
+// Redeem bloquea la cita para verificar su pendiente y descuenta el saldo con
+// un UPDATE condicional: si dos canjes llegan a la vez solo se aplican los
+// que alcanzan a cubrir el saldo de la tarjeta y el pendiente de la cita
+func (r *GormVoucherRepo) Redeem(ctx context.Context, vtx *domain.VoucherTransaction, now time.Time, check domain.DueCheck) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := lockAppointment(tx, vtx.AppointmentID); err != nil {
+			return err
+		}
+
+		// El pendiente se calcula dentro de la transaccion, con la cita bloqueada
+		if err := check(withTx(ctx, tx)); err != nil {
+			return err
+		}
+
+		res := tx.Model(&domain.Voucher{}).
+			Where("id = ? AND balance_currency = ? AND balance_cents >= ?", vtx.VoucherID, vtx.Amount.Currency, vtx.Amount.Cents).
+			Where("expires_at IS NULL OR expires_at > ?", now).
+			Updates(map[string]any{
+				"balance_cents": gorm.Expr("balance_cents - ?", vtx.Amount.Cents),
+				"updated_at":    now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return domain.ErrorInsufficientFunds
+		}
+
+		return tx.Create(vtx).Error
+	})
+}
+
+func (r *GormVoucherRepo) Refund(ctx context.Context, vtx *domain.VoucherTransaction) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.Voucher{}).
+			Where("id = ?", vtx.VoucherID).
+			Updates(map[string]any{
+				"balance_cents": gorm.Expr("balance_cents + ?", vtx.Amount.Cents),
+				"updated_at":    time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(vtx).Error
+	})
+}
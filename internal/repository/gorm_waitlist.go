@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormWaitlistRepo struct {
+	db *gorm.DB
+}
+
+func NewGormWaitlistRepo(db *gorm.DB) domain.WaitlistRepo {
+	return &GormWaitlistRepo{db}
+}
+
+func (r *GormWaitlistRepo) Create(ctx context.Context, entry *domain.WaitlistEntry) error {
+	return conn(ctx, r.db).Create(entry).Error
+}
+
+func (r *GormWaitlistRepo) GetById(ctx context.Context, id uint) (*domain.WaitlistEntry, error) {
+	var entry domain.WaitlistEntry
+
+	if err := conn(ctx, r.db).Preload("Products").Preload("Client").First(&entry, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrorNotFound
+		}
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func (r *GormWaitlistRepo) List(ctx context.Context, status domain.WaitlistStatus) ([]domain.WaitlistEntry, error) {
+	var entries []domain.WaitlistEntry
+
+	query := conn(ctx, r.db).Preload("Products").Preload("Client")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Order("created_at, id").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *GormWaitlistRepo) ListByClient(ctx context.Context, clientID uint) ([]domain.WaitlistEntry, error) {
+	var entries []domain.WaitlistEntry
+
+	err := conn(ctx, r.db).Preload("Products").
+		Where("client_id = ?", clientID).
+		Order("created_at, id").
+		Find(&entries).Error
+
+	return entries, err
+}
+
+func (r *GormWaitlistRepo) Update(ctx context.Context, entry *domain.WaitlistEntry) error {
+	return conn(ctx, r.db).Save(entry).Error
+}
+
+// UpdateFrom guarda la solicitud con un UPDATE condicionado a su estado
+// actual, para que dos cambios simultaneos no la procesen dos veces
+func (r *GormWaitlistRepo) UpdateFrom(ctx context.Context, entry *domain.WaitlistEntry, from ...domain.WaitlistStatus) error {
+	res := conn(ctx, r.db).Model(entry).
+		Select("*").Omit(clause.Associations).
+		Where("status IN ?", from).
+		Updates(entry)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return domain.ErrorWaitlistChanged
+	}
+
+	return nil
+}
+
+// LockBarber bloquea la fila del barbero hasta el fin de la transaccion, para
+// que dos horarios liberados a la vez no se ofrezcan a dos clientes
+func (r *GormWaitlistRepo) LockBarber(ctx context.Context, barberID uint) error {
+	var barber domain.Barber
+	if err := conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").First(&barber, barberID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return domain.ErrorNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// FindCandidates devuelve, en orden de llegada, las solicitudes en espera
+// cuya ventana se cruza con el horario liberado y aceptan a ese barbero
+func (r *GormWaitlistRepo) FindCandidates(ctx context.Context, barberID uint, start, end time.Time) ([]domain.WaitlistEntry, error) {
+	var entries []domain.WaitlistEntry
+
+	if err := conn(ctx, r.db).Preload("Products").
+		Where("status = ?", domain.WaitlistWaiting).
+		Where("window_start < ? AND window_end > ?", end, start).
+		Where("(barber_id IS NULL OR barber_id = ?)", barberID).
+		Order("created_at, id").
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// FindActiveHolds devuelve las ofertas vigentes que reservan parte del rango
+func (r *GormWaitlistRepo) FindActiveHolds(ctx context.Context, barberID uint, start, end, now time.Time) ([]domain.WaitlistEntry, error) {
+	var entries []domain.WaitlistEntry
+
+	if err := conn(ctx, r.db).
+		Where("status = ? AND hold_expires_at > ?", domain.WaitlistOffered, now).
+		Where("offer_barber_id = ? AND offer_start < ? AND offer_end > ?", barberID, end, start).
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ClaimExpiredHolds devuelve a la espera las ofertas vencidas en una sola
+// sentencia, para que cada oferta la procese un unico llamador aunque el
+// vencimiento corra a la vez desde varios lugares. Las solicitudes devueltas
+// traen el horario que tenian ofrecido para poder ofrecerlo al siguiente.
+func (r *GormWaitlistRepo) ClaimExpiredHolds(ctx context.Context, now time.Time) ([]domain.WaitlistEntry, error) {
+	var entries []domain.WaitlistEntry
+
+	if err := conn(ctx, r.db).Raw(`
+		UPDATE waitlist_entries w
+		SET status = ?, offer_barber_id = NULL, offer_start = NULL, offer_end = NULL,
+		    hold_expires_at = NULL, updated_at = ?
+		FROM (
+		  SELECT id, offer_barber_id, offer_start, offer_end
+		  FROM waitlist_entries
+		  WHERE status = ? AND hold_expires_at <= ?
+		  FOR UPDATE SKIP LOCKED
+		) held
+		WHERE w.id = held.id
+		RETURNING w.id, held.offer_barber_id, held.offer_start, held.offer_end`,
+		domain.WaitlistWaiting, now, domain.WaitlistOffered, now,
+	).Scan(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ExpirePastWindows marca como vencidas las solicitudes en espera cuya
+// ventana deseada ya paso
+func (r *GormWaitlistRepo) ExpirePastWindows(ctx context.Context, now time.Time) error {
+	return conn(ctx, r.db).Model(&domain.WaitlistEntry{}).
+		Where("status = ? AND window_end <= ?", domain.WaitlistWaiting, now).
+		Updates(map[string]any{"status": domain.WaitlistExpired, "updated_at": now}).Error
+}
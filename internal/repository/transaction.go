@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// GormTransactor agrupa operaciones de varios repositorios en una transaccion
+type GormTransactor struct {
+	db *gorm.DB
+}
+
+func NewGormTransactor(db *gorm.DB) domain.Transactor {
+	return &GormTransactor{db}
+}
+
+// InTransaction ejecuta fn en una transaccion. Los repositorios llamados con
+// el contexto que recibe fn trabajan dentro de ella; si ya habia una abierta
+// se usa un savepoint.
+func (t *GormTransactor) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return conn(ctx, t.db).Transaction(func(tx *gorm.DB) error {
+		return fn(withTx(ctx, tx))
+	})
+}
+
+// conn devuelve la transaccion abierta en el contexto o, si no hay ninguna,
+// la conexion del repositorio
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// withTx guarda la transaccion en el contexto para los repositorios que se
+// llamen con el
+func withTx(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
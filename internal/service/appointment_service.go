@@ -3,139 +3,1027 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/alexnt4/barber-api/internal/domain"
 )
 
+// appointmentTransitions define los cambios de estado permitidos
+var appointmentTransitions = map[domain.AppointmentStatus][]domain.AppointmentStatus{
+	domain.StatusScheduled:  {domain.StatusConfirmed, domain.StatusCheckedIn, domain.StatusNoShow, domain.StatusCancelled},
+	domain.StatusConfirmed:  {domain.StatusCheckedIn, domain.StatusNoShow, domain.StatusCancelled},
+	domain.StatusCheckedIn:  {domain.StatusInProgress, domain.StatusCancelled},
+	domain.StatusInProgress: {domain.StatusCompleted},
+}
+
+// StatusListener recibe cada cambio de estado de una cita dentro de la
+// transaccion que lo guarda. Si devuelve un error el cambio se descarta.
+type StatusListener interface {
+	StatusChanged(ctx context.Context, appt *domain.Appointment, from domain.AppointmentStatus) error
+}
+
+// SlotHolder informa los horarios reservados temporalmente, que no pueden
+// agendarse aunque no tengan una cita
+type SlotHolder interface {
+	HeldSlots(ctx context.Context, barberID uint, start, end time.Time) ([]domain.AvailableSlot, error)
+}
+
+// TotalAdjuster aporta descuentos o pagos que se restan del total de una cita
+type TotalAdjuster interface {
+	Adjustments(ctx context.Context, appt *domain.Appointment) ([]domain.TotalLine, error)
+}
+
+// PaymentSource informa los pagos ya registrados sobre una cita
+type PaymentSource interface {
+	Payments(ctx context.Context, appt *domain.Appointment) ([]domain.TotalLine, error)
+}
+
 type AppointmentService struct {
-	apptRepo domain.AppointmentRepo
-	prodRepo domain.ProductRepo
+	apptRepo   domain.AppointmentRepo
+	prodRepo   domain.ProductRepo
+	barberRepo domain.BarberRepo
+	clientRepo domain.ClientRepo
+	seriesRepo domain.AppointmentSeriesRepo
+	calendar   *CalendarService
+	tokens     *ManageTokens
+	tx         domain.Transactor
+	policy     CancellationPolicy
+	feeRepo    domain.FeeRepo
+	noShowRule NoShowRule
+	holder     SlotHolder
+	listeners  []StatusListener
+	adjusters  []TotalAdjuster
+	payments   []PaymentSource
+}
+
+func NewAppointmentService(a domain.AppointmentRepo, p domain.ProductRepo, b domain.BarberRepo, cl domain.ClientRepo, sr domain.AppointmentSeriesRepo, c *CalendarService, t *ManageTokens, tx domain.Transactor) *AppointmentService {
+	return &AppointmentService{apptRepo: a, prodRepo: p, barberRepo: b, clientRepo: cl, seriesRepo: sr, calendar: c, tokens: t, tx: tx}
 }
 
-func NewAppointmentService(a domain.AppointmentRepo, p domain.ProductRepo) *AppointmentService {
-	return &AppointmentService{a, p}
+// SetSlotHolder registra quien informa las reservas temporales de horarios
+func (s *AppointmentService) SetSlotHolder(h SlotHolder) {
+	s.holder = h
+}
+
+// AddStatusListener registra un interesado en los cambios de estado
+func (s *AppointmentService) AddStatusListener(l StatusListener) {
+	s.listeners = append(s.listeners, l)
 }
 
 func (s *AppointmentService) Schedule(ctx context.Context, appt *domain.Appointment) error {
-	// 1. Validar que la cita sea en el futuro
+	// 1. Validar existencia de productos y guardar su precio actual
+	products, err := s.loadItems(ctx, appt.Items)
+	if err != nil {
+		return err
+	}
+
+	return s.schedule(ctx, appt, products)
+}
+
+// schedule agenda la cita cuyas lineas ya se cargaron con loadItems
+func (s *AppointmentService) schedule(ctx context.Context, appt *domain.Appointment, products []domain.Product) error {
+	// 2. Calcular o validar la hora de fin segun la duracion de los servicios
+	if err := s.resolveEndTime(appt, products); err != nil {
+		return err
+	}
+
+	// 3. Validar que la cita sea en el futuro
 	if appt.StartTime.Before(time.Now()) {
 		return errors.New("la cita no puede ser en el pasado")
 	}
 
-	// 2. Validar que el tiempo de fin sea despues del inicio
-	if !appt.EndTime.After(appt.StartTime) {
-		return errors.New("la hora de fin debe ser posterior a la de inicio")
+	// 4. Validar el horario de atencion de la barberia
+	if err := s.calendar.ValidateWithinHours(ctx, appt.StartTime, appt.EndTime); err != nil {
+		return err
+	}
+
+	// 5. Validar que el cliente y el barbero existan
+	if err := s.validateClient(ctx, appt.ClientID); err != nil {
+		return err
+	}
+
+	if err := s.validateBarber(ctx, appt.BarberID); err != nil {
+		return err
+	}
+
+	// Los clientes con inasistencias repetidas pueden quedar bloqueados o
+	// deber una sena
+	if err := s.applyNoShowRule(ctx, appt); err != nil {
+		return err
 	}
 
-	// 3. Evitar solapamiento de turnos
-	existinAppts, err := s.apptRepo.List(ctx)
+	// 6. Evitar solapamiento de turnos del mismo barbero. La base de datos
+	// vuelve a verificarlo al insertar para evitar reservas simultaneas.
+	overlapping, err := s.apptRepo.FindOverlapping(ctx, appt.BarberID, appt.StartTime, appt.EndTime, 0)
 	if err != nil {
 		return err
 	}
 
-	for _, existing := range existinAppts {
-		// to do : implementar logica de solapamiento
-		if s.appointmentsOverlap(appt, &existing) {
-			return errors.New("el turno se solapa con otro existente")
-		}
+	if len(overlapping) > 0 {
+		return domain.ErrorOverlap
 	}
 
-	// 4. Validar existencia de productos
-	for i, prod := range appt.Products {
-		existingProd, err := s.prodRepo.GetById(ctx, prod.ID)
-		if err != nil {
-			if err == domain.ErrorNotFound {
-				return errors.New("producto no encontrado")
-			}
+	if err := s.checkHolds(ctx, appt); err != nil {
+		return err
+	}
+
+	// 7. Crear turno
+	appt.Status = domain.StatusScheduled
+	if err := s.apptRepo.Create(ctx, appt); err != nil {
+		return err
+	}
+
+	// 8. Generar el token para que el cliente administre su cita
+	appt.ManageToken = s.tokens.Sign(appt.ID, appt.TokenVersion, appt.StartTime)
+	return nil
+}
+
+// ScheduleRecurring crea una serie de citas a partir de una regla de recurrencia.
+// Cada ocurrencia pasa por las mismas validaciones que Schedule; las que no
+// pueden agendarse se devuelven como conflictos y el resto se crea igual. La
+// serie y sus citas se guardan en una transaccion, con un savepoint por
+// ocurrencia para que un conflicto no descarte las demas.
+func (s *AppointmentService) ScheduleRecurring(ctx context.Context, appt *domain.Appointment, rule string) (*domain.AppointmentSeries, []domain.Appointment, []domain.OccurrenceConflict, error) {
+	loc := s.calendar.Location()
+	recurrence, err := parseRecurrenceRule(rule, loc)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	series := &domain.AppointmentSeries{RRule: rule}
+	created := []domain.Appointment{}
+	conflicts := []domain.OccurrenceConflict{}
+
+	err = s.tx.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.seriesRepo.Create(ctx, series); err != nil {
 			return err
 		}
 
-		// Actualizar con los datos completos del producto
-		appt.Products[i] = *existingProd
+		// Las ocurrencias conservan la hora de reloj de la barberia aunque
+		// cambie el horario de verano
+		for _, start := range recurrence.occurrences(appt.StartTime.In(loc)) {
+			occurrence := shiftAppointment(appt, start)
+			occurrence.SeriesID = &series.ID
+
+			err := s.tx.InTransaction(ctx, func(ctx context.Context) error {
+				return s.Schedule(ctx, occurrence)
+			})
+			if err != nil {
+				conflicts = append(conflicts, domain.OccurrenceConflict{
+					StartTime: start,
+					Error:     err.Error(),
+				})
+				continue
+			}
+
+			created = append(created, *occurrence)
+		}
+
+		// Si ninguna ocurrencia pudo agendarse la serie no tiene sentido
+		if len(created) == 0 {
+			return errors.New("ninguna ocurrencia de la serie pudo agendarse")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, conflicts, err
 	}
-	// 5. Crear turno
-	return s.apptRepo.Create(ctx, appt)
+
+	return series, created, conflicts, nil
 }
 
 func (s *AppointmentService) GetById(ctx context.Context, id uint) (*domain.Appointment, error) {
 	return s.apptRepo.GetById(ctx, id)
 }
 
-func (s *AppointmentService) ListAll(ctx context.Context) ([]domain.Appointment, error) {
-	return s.apptRepo.List(ctx)
+func (s *AppointmentService) List(ctx context.Context, opts domain.AppointmentListOptions) ([]domain.Appointment, string, error) {
+	return s.apptRepo.List(ctx, opts)
 }
 
 func (s *AppointmentService) Update(ctx context.Context, id uint, updatedAppt *domain.Appointment) error {
+	return s.update(ctx, id, updatedAppt, false)
+}
+
+// update modifica la cita. Las modificaciones que el cliente hace por su
+// cuenta (online) pasan ademas por la restriccion por inasistencias.
+func (s *AppointmentService) update(ctx context.Context, id uint, updatedAppt *domain.Appointment, online bool) error {
 	// Verificar que la cita existe
 	existing, err := s.apptRepo.GetById(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	// Mantener el ID original
+	// Solo se pueden modificar citas que aun no fueron atendidas
+	if existing.Status != domain.StatusScheduled && existing.Status != domain.StatusConfirmed {
+		return fmt.Errorf("no se puede modificar una cita en estado %s", existing.Status)
+	}
+
+	// Mantener el ID, el estado y su historial
 	updatedAppt.ID = existing.ID
+	updatedAppt.Status = existing.Status
+	updatedAppt.ConfirmedAt = existing.ConfirmedAt
+	updatedAppt.SeriesID = existing.SeriesID
+	updatedAppt.Deposit = existing.Deposit
+	updatedAppt.DepositPaidAt = existing.DepositPaidAt
+	updatedAppt.CreatedAt = existing.CreatedAt
+
+	// Reprogramar desde el token emite uno nuevo y revoca el anterior
+	updatedAppt.TokenVersion = existing.TokenVersion
+	if online {
+		updatedAppt.TokenVersion++
+	}
+
+	// Los productos que ya tenia la cita conservan su linea y su precio
+	keepItems(existing.Items, updatedAppt.Items)
+
+	// Validar existencia de productos
+	products, err := s.loadItems(ctx, updatedAppt.Items)
+	if err != nil {
+		return err
+	}
+
+	// La sena pendiente acompana a los servicios actualizados
+	if updatedAppt.Deposit.IsPositive() && updatedAppt.DepositPaidAt == nil {
+		updatedAppt.Deposit = appointmentTotal(updatedAppt).Percent(s.noShowRule.DepositPercent)
+	}
+
+	if online {
+		if err := s.applyNoShowRule(ctx, updatedAppt); err != nil {
+			return err
+		}
+	}
 
 	// Validar horarios
+	if err := s.resolveEndTime(updatedAppt, products); err != nil {
+		return err
+	}
+
 	if updatedAppt.StartTime.Before(time.Now()) {
 		return errors.New("la cita no puede ser en el pasado")
 	}
 
-	if !updatedAppt.EndTime.After(updatedAppt.StartTime) {
-		return errors.New("la hora de fin debe ser posterior a la de inicio")
+	if err := s.calendar.ValidateWithinHours(ctx, updatedAppt.StartTime, updatedAppt.EndTime); err != nil {
+		return err
+	}
+
+	// Validar cliente y barbero
+	if err := s.validateClient(ctx, updatedAppt.ClientID); err != nil {
+		return err
 	}
 
-	// Verificar solapamiento con otros turnos
-	existingAppts, err := s.apptRepo.List(ctx)
+	if err := s.validateBarber(ctx, updatedAppt.BarberID); err != nil {
+		return err
+	}
+
+	// Verificar solapamiento con otros turnos del mismo barbero
+	overlapping, err := s.apptRepo.FindOverlapping(ctx, updatedAppt.BarberID, updatedAppt.StartTime, updatedAppt.EndTime, id)
 	if err != nil {
 		return err
 	}
 
-	for _, existing := range existingAppts {
-		if existing.ID != id && s.appointmentsOverlap(updatedAppt, &existing) {
-			return errors.New("el turno se solopa con otro existente")
+	if len(overlapping) > 0 {
+		return domain.ErrorOverlap
+	}
+
+	if err := s.checkHolds(ctx, updatedAppt); err != nil {
+		return err
+	}
+
+	return s.apptRepo.Update(ctx, updatedAppt)
+}
+
+// UpdateFollowing aplica los cambios a la cita y a las siguientes de su serie.
+// El desplazamiento de horario respecto de la cita original se replica en
+// cada ocurrencia; las que no pueden modificarse se devuelven como conflictos.
+func (s *AppointmentService) UpdateFollowing(ctx context.Context, id uint, updatedAppt *domain.Appointment) ([]domain.Appointment, []domain.OccurrenceConflict, error) {
+	following, err := s.followingInSeries(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shift := updatedAppt.StartTime.Sub(following[0].StartTime)
+
+	updated := []domain.Appointment{}
+	conflicts := []domain.OccurrenceConflict{}
+	for _, existing := range following {
+		if existing.Status != domain.StatusScheduled && existing.Status != domain.StatusConfirmed {
+			continue
+		}
+
+		occurrence := shiftAppointment(updatedAppt, existing.StartTime.Add(shift))
+
+		if err := s.Update(ctx, existing.ID, occurrence); err != nil {
+			conflicts = append(conflicts, domain.OccurrenceConflict{
+				AppointmentID: existing.ID,
+				StartTime:     occurrence.StartTime,
+				Error:         err.Error(),
+			})
+			continue
 		}
+
+		updated = append(updated, *occurrence)
 	}
 
-	// Validar existencia de productos
-	for i, prod := range updatedAppt.Products {
+	return updated, conflicts, nil
+}
+
+// Cancel cancela la cita aplicando la politica de cancelacion. Si ya paso el
+// plazo gratuito se devuelve el cargo generado al cliente, o un error cuando
+// la politica no admite cancelaciones tardias.
+func (s *AppointmentService) Cancel(ctx context.Context, id uint) (*domain.Appointment, *domain.Fee, error) {
+	appt, err := s.apptRepo.GetById(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !canTransition(appt.Status, domain.StatusCancelled) {
+		return nil, nil, fmt.Errorf("%w: de %s a %s", domain.ErrorInvalidTransition, appt.Status, domain.StatusCancelled)
+	}
+
+	var fee *domain.Fee
+	if s.policy.isLate(appt, time.Now()) {
+		if !s.policy.AllowLateCancel {
+			return nil, nil, fmt.Errorf("%w: debe cancelarse con %s de anticipacion", domain.ErrorCancelCutoff, s.policy.FreeCancelWindow)
+		}
+		fee = s.policy.newFee(appt, domain.FeeLateCancel, s.policy.LateCancelFeePercent)
+	}
+
+	appt, err = s.transitionWithFee(ctx, appt, domain.StatusCancelled, fee)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return appt, fee, nil
+}
+
+// SetCancellationPolicy configura la politica de cancelacion y donde se
+// registran los cargos. Sin repositorio de cargos no se cobra nada.
+func (s *AppointmentService) SetCancellationPolicy(policy CancellationPolicy, fees domain.FeeRepo) {
+	s.policy = policy
+	s.feeRepo = fees
+}
+
+// GetByManageToken devuelve la cita a la que da acceso el token. Los tokens
+// reemplazados al reprogramar ya no dan acceso.
+func (s *AppointmentService) GetByManageToken(ctx context.Context, token string) (*domain.Appointment, error) {
+	id, version, err := s.tokens.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	appt, err := s.apptRepo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if appt.TokenVersion != version {
+		return nil, domain.ErrorInvalidToken
+	}
+
+	return appt, nil
+}
+
+// RescheduleByToken mueve la cita del token a otra hora de inicio, conservando
+// cliente, barbero, servicios y duracion. Devuelve la cita con un token nuevo
+// que vence con el nuevo horario; el token usado deja de ser valido.
+func (s *AppointmentService) RescheduleByToken(ctx context.Context, token string, start time.Time) (*domain.Appointment, error) {
+	existing, err := s.GetByManageToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fuera del plazo gratuito el cliente no puede mover la cita, asi no la
+	// aleja del plazo para luego cancelarla sin cargo
+	if s.policy.isLate(existing, time.Now()) {
+		return nil, fmt.Errorf("%w: debe reprogramarse con %s de anticipacion", domain.ErrorCancelCutoff, s.policy.FreeCancelWindow)
+	}
+
+	updated := &domain.Appointment{
+		ClientID:  existing.ClientID,
+		BarberID:  existing.BarberID,
+		StartTime: start,
+		EndTime:   start.Add(existing.EndTime.Sub(existing.StartTime)),
+		Items:     existing.Items,
+	}
+
+	if err := s.update(ctx, existing.ID, updated, true); err != nil {
+		return nil, err
+	}
+
+	updated.ManageToken = s.tokens.Sign(updated.ID, updated.TokenVersion, updated.StartTime)
+	return updated, nil
+}
+
+// CancelByToken cancela la cita a la que da acceso el token
+func (s *AppointmentService) CancelByToken(ctx context.Context, token string) (*domain.Appointment, *domain.Fee, error) {
+	appt, err := s.GetByManageToken(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.Cancel(ctx, appt.ID)
+}
+
+// CancelFollowing cancela la cita y las siguientes de su serie que sigan
+// activas. Devuelve los cargos generados y las citas que no pudieron
+// cancelarse, sin detenerse en ellas.
+func (s *AppointmentService) CancelFollowing(ctx context.Context, id uint) ([]domain.Appointment, []domain.Fee, []domain.OccurrenceConflict, error) {
+	following, err := s.followingInSeries(ctx, id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	cancelled := []domain.Appointment{}
+	fees := []domain.Fee{}
+	conflicts := []domain.OccurrenceConflict{}
+	for _, existing := range following {
+		if !canTransition(existing.Status, domain.StatusCancelled) {
+			continue
+		}
+
+		appt, fee, err := s.Cancel(ctx, existing.ID)
+		if err != nil {
+			conflicts = append(conflicts, domain.OccurrenceConflict{
+				AppointmentID: existing.ID,
+				StartTime:     existing.StartTime,
+				Error:         err.Error(),
+			})
+			continue
+		}
+
+		cancelled = append(cancelled, *appt)
+		if fee != nil {
+			fees = append(fees, *fee)
+		}
+	}
+
+	return cancelled, fees, conflicts, nil
+}
+
+// followingInSeries devuelve la cita indicada y las posteriores de su serie
+func (s *AppointmentService) followingInSeries(ctx context.Context, id uint) ([]domain.Appointment, error) {
+	appt, err := s.apptRepo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if appt.SeriesID == nil {
+		return nil, errors.New("la cita no pertenece a una serie")
+	}
+
+	series, err := s.apptRepo.ListBySeries(ctx, *appt.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	following := []domain.Appointment{*appt}
+	for _, existing := range series {
+		if existing.StartTime.After(appt.StartTime) {
+			following = append(following, existing)
+		}
+	}
+
+	return following, nil
+}
+
+// Transition cambia el estado de la cita validando que el cambio este
+// permitido y registra la hora en que ocurrio
+func (s *AppointmentService) Transition(ctx context.Context, id uint, to domain.AppointmentStatus) (*domain.Appointment, error) {
+	// La cancelacion pasa por la politica de cancelacion
+	if to == domain.StatusCancelled {
+		appt, _, err := s.Cancel(ctx, id)
+		return appt, err
+	}
+
+	appt, err := s.apptRepo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Las inasistencias generan un cargo segun la politica
+	var fee *domain.Fee
+	if to == domain.StatusNoShow {
+		fee = s.policy.newFee(appt, domain.FeeNoShow, s.policy.NoShowFeePercent)
+	}
+
+	return s.transitionWithFee(ctx, appt, to, fee)
+}
+
+// transitionWithFee aplica el cambio de estado registrando el cargo y
+// notificando a los interesados en la misma transaccion. Sin repositorio de
+// cargos el cargo se ignora.
+func (s *AppointmentService) transitionWithFee(ctx context.Context, appt *domain.Appointment, to domain.AppointmentStatus, fee *domain.Fee) (*domain.Appointment, error) {
+	if !canTransition(appt.Status, to) {
+		return nil, fmt.Errorf("%w: de %s a %s", domain.ErrorInvalidTransition, appt.Status, to)
+	}
+
+	now := time.Now()
+	if err := s.checkTransitionTime(appt, to, now); err != nil {
+		return nil, err
+	}
+
+	// Las citas con sena pendiente no avanzan hasta que se pague
+	if appt.Deposit.IsPositive() && appt.DepositPaidAt == nil &&
+		(to == domain.StatusConfirmed || to == domain.StatusCheckedIn) {
+		return nil, domain.ErrorDepositRequired
+	}
+
+	from := appt.Status
+	switch to {
+	case domain.StatusConfirmed:
+		appt.ConfirmedAt = &now
+	case domain.StatusCheckedIn:
+		appt.CheckedInAt = &now
+	case domain.StatusInProgress:
+		appt.StartedAt = &now
+	case domain.StatusCompleted:
+		appt.CompletedAt = &now
+	case domain.StatusNoShow:
+		appt.NoShowAt = &now
+	case domain.StatusCancelled:
+		appt.CancelledAt = &now
+	}
+	appt.Status = to
+
+	if s.feeRepo == nil {
+		fee = nil
+	}
+
+	// Lo que hacen los interesados (devolver saldos y puntos, liberar la
+	// promocion, ofrecer el horario) se guarda junto con el cambio de estado
+	err := s.tx.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.apptRepo.UpdateWithFee(ctx, appt, fee); err != nil {
+			return err
+		}
+
+		for _, l := range s.listeners {
+			if err := l.StatusChanged(ctx, appt, from); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return appt, nil
+}
+
+// checkTransitionTime impide registrar una inasistencia antes de que empiece
+// la cita y la llegada del cliente antes del dia de la cita. La atencion y su
+// fin dependen de la llegada, asi que no necesitan otra verificacion.
+func (s *AppointmentService) checkTransitionTime(appt *domain.Appointment, to domain.AppointmentStatus, now time.Time) error {
+	switch to {
+	case domain.StatusNoShow:
+		if now.Before(appt.StartTime) {
+			return fmt.Errorf("%w: la cita todavia no empezo", domain.ErrorInvalidTransition)
+		}
+	case domain.StatusCheckedIn:
+		y, m, d := appt.StartTime.In(s.calendar.Location()).Date()
+		day := time.Date(y, m, d, 0, 0, 0, 0, s.calendar.Location())
+		if now.Before(day) {
+			return fmt.Errorf("%w: la llegada se registra el dia de la cita", domain.ErrorInvalidTransition)
+		}
+	}
+
+	return nil
+}
+
+// AddTotalAdjuster registra quien aporta descuentos al total de las citas
+func (s *AppointmentService) AddTotalAdjuster(a TotalAdjuster) {
+	s.adjusters = append(s.adjusters, a)
+}
+
+// AddPaymentSource registra quien informa pagos sobre las citas
+func (s *AppointmentService) AddPaymentSource(p PaymentSource) {
+	s.payments = append(s.payments, p)
+}
+
+// GetTotalPrice devuelve el subtotal de los servicios de la cita, los
+// descuentos aplicados, el total, los pagos registrados y el saldo pendiente
+func (s *AppointmentService) GetTotalPrice(ctx context.Context, id uint) (*domain.AppointmentTotal, error) {
+	appt, err := s.apptRepo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	total := &domain.AppointmentTotal{
+		AppointmentID: appt.ID,
+		Lines:         itemLines(appt),
+		Subtotal:      appointmentTotal(appt),
+		Discounts:     []domain.TotalLine{},
+	}
+
+	total.Total = total.Subtotal
+	for _, a := range s.adjusters {
+		lines, err := a.Adjustments(ctx, appt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, line := range lines {
+			total.Discounts = append(total.Discounts, line)
+			total.Total = total.Total.Sub(line.Amount)
+		}
+	}
+
+	total.Total = total.Total.NonNegative()
+
+	// Impuestos por tarifa; se suma el de los precios que no lo incluyen
+	var added domain.Money
+	total.Taxes, added = appointmentTaxes(appt.Items, total.Discounts)
+	total.TaxTotal = domain.NewMoney(0, total.Subtotal.Currency)
+	for _, line := range total.Taxes {
+		total.TaxTotal = total.TaxTotal.Add(line.Tax)
+	}
+	total.Total = total.Total.Add(added)
+
+	payments, err := s.appointmentPayments(ctx, appt)
+	if err != nil {
+		return nil, err
+	}
+	applyPayments(total, payments)
+
+	return total, nil
+}
+
+// appointmentPayments devuelve la sena pagada y los pagos registrados sobre
+// la cita
+func (s *AppointmentService) appointmentPayments(ctx context.Context, appt *domain.Appointment) ([]domain.TotalLine, error) {
+	payments := []domain.TotalLine{}
+
+	// La sena pagada cuenta como pago a cuenta
+	if appt.DepositPaidAt != nil {
+		payments = append(payments, domain.TotalLine{
+			Kind:        "deposit",
+			Description: "Sena",
+			Amount:      appt.Deposit,
+		})
+	}
+
+	for _, p := range s.payments {
+		lines, err := p.Payments(ctx, appt)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, lines...)
+	}
+
+	return payments, nil
+}
+
+// applyPayments registra los pagos en el total y calcula el pendiente
+func applyPayments(total *domain.AppointmentTotal, payments []domain.TotalLine) {
+	total.Payments = payments
+	total.Due = total.Total
+	for _, line := range total.Payments {
+		total.Due = total.Due.Sub(line.Amount)
+	}
+	total.Due = total.Due.NonNegative()
+}
+
+// dueCheck verifica que el monto no supere el pendiente actual de la cita.
+// Los repositorios la ejecutan con la cita bloqueada al registrar un pago.
+func (s *AppointmentService) dueCheck(apptID uint, amount domain.Money) domain.DueCheck {
+	return func(ctx context.Context) error {
+		total, err := s.GetTotalPrice(ctx, apptID)
+		if err != nil {
+			return err
+		}
+
+		if amount.Cents > total.Due.Cents {
+			return fmt.Errorf("%w: el monto supera el pendiente de la cita", domain.ErrorInvalidInput)
+		}
+
+		return nil
+	}
+}
+
+// itemLines arma el detalle de las lineas cobradas en la cita
+func itemLines(appt *domain.Appointment) []domain.TotalLine {
+	lines := make([]domain.TotalLine, len(appt.Items))
+	for i, item := range appt.Items {
+		kind := "product"
+		if item.IsCustom() {
+			kind = "custom"
+		}
+
+		unitPrice := item.UnitPrice
+		lines[i] = domain.TotalLine{
+			Kind:        kind,
+			Description: item.Name,
+			Quantity:    item.Quantity,
+			UnitPrice:   &unitPrice,
+			Amount:      item.Total(),
+		}
+		if item.IsOverridden() {
+			lines[i].Note = fmt.Sprintf("precio de lista %s: %s", item.ListPrice, item.OverrideReason)
+		}
+	}
+
+	return lines
+}
+
+// appointmentTotal suma las lineas de la cita con el precio registrado al
+// agendar. Una cita sin lineas vale cero en la moneda de la barberia.
+func appointmentTotal(appt *domain.Appointment) domain.Money {
+	var total domain.Money
+	for _, item := range appt.Items {
+		total = total.Add(item.Total())
+	}
+
+	if total.Currency == "" {
+		total.Currency = domain.DefaultCurrency
+	}
+
+	return total
+}
+
+// loadProducts reemplaza cada producto por su version completa guardada
+func (s *AppointmentService) loadProducts(ctx context.Context, products []domain.Product) error {
+	for i, prod := range products {
 		existingProd, err := s.prodRepo.GetById(ctx, prod.ID)
 		if err != nil {
 			if err == domain.ErrorNotFound {
-				return errors.New("producto no encontrado")
+				return fmt.Errorf("producto %d no encontrado: %w", prod.ID, err)
 			}
 			return err
 		}
 
 		// Actualizar con los datos completos del producto
-		updatedAppt.Products[i] = *existingProd
+		products[i] = *existingProd
+
+		// Los precios de una misma cita deben poder sumarse
+		if !existingProd.Price.SameCurrency(products[0].Price) {
+			return fmt.Errorf("%w: los servicios elegidos tienen precios en monedas distintas", domain.ErrorCurrencyMismatch)
+		}
 	}
 
-	return s.apptRepo.Update(ctx, updatedAppt)
+	return nil
 }
 
-func (s *AppointmentService) Cancel(ctx context.Context, id uint) error {
-	// Verificar que la cita existe
-	_, err := s.apptRepo.GetById(ctx, id)
+// loadItems valida las lineas de la cita y devuelve un producto por unidad
+// para calcular la duracion del turno. Las lineas nuevas de productos
+// registran el nombre y el precio actual, o el precio indicado con su motivo;
+// las lineas ya registradas conservan su precio. Las lineas libres no tienen
+// producto y no suman duracion.
+func (s *AppointmentService) loadItems(ctx context.Context, items []domain.AppointmentItem) ([]domain.Product, error) {
+	var products []domain.Product
+	for i := range items {
+		item := &items[i]
+
+		if item.Quantity < 0 {
+			return nil, errors.New("la cantidad debe ser mayor a cero")
+		}
+		if item.Quantity == 0 {
+			item.Quantity = 1
+		}
+
+		if item.UnitPrice.IsNegative() {
+			return nil, errors.New("el precio de la linea no puede ser negativo")
+		}
+
+		if item.IsCustom() {
+			if err := validateCustomItem(item); err != nil {
+				return nil, err
+			}
+		} else {
+			prod, err := s.prodRepo.GetById(ctx, *item.ProductID)
+			if err != nil {
+				if err == domain.ErrorNotFound {
+					return nil, errors.New("producto no encontrado")
+				}
+				return nil, err
+			}
+
+			if item.Name == "" {
+				if err := recordItemPrice(item, prod); err != nil {
+					return nil, err
+				}
+			}
+
+			for range item.Quantity {
+				products = append(products, *prod)
+			}
+		}
+
+		// Los precios de una misma cita deben poder sumarse
+		if !item.UnitPrice.SameCurrency(items[0].UnitPrice) {
+			return nil, fmt.Errorf("%w: los servicios elegidos tienen precios en monedas distintas", domain.ErrorCurrencyMismatch)
+		}
+	}
+
+	return products, nil
+}
+
+// recordItemPrice registra en una linea nueva el nombre y el precio del
+// producto. Si la linea trae un precio distinto se guarda el de lista y se
+// exige el motivo del ajuste.
+func recordItemPrice(item *domain.AppointmentItem, prod *domain.Product) error {
+	item.Name = prod.Name
+	item.Category = prod.Category
+	item.Tax = prod.Tax
+	item.OverrideReason = strings.TrimSpace(item.OverrideReason)
+
+	if item.UnitPrice.Currency == "" {
+		if item.OverrideReason != "" {
+			return errors.New("debe indicar el precio de la linea ajustada")
+		}
+		item.UnitPrice = prod.Price
+		return nil
+	}
+
+	if !item.UnitPrice.SameCurrency(prod.Price) {
+		return fmt.Errorf("%w: el precio ajustado debe estar en %s", domain.ErrorCurrencyMismatch, prod.Price.Currency)
+	}
+
+	if item.OverrideReason == "" {
+		return errors.New("el motivo es requerido para cambiar el precio de una linea")
+	}
+
+	item.ListPrice = prod.Price
+	return nil
+}
+
+// validateCustomItem valida una linea libre, sin producto del catalogo
+func validateCustomItem(item *domain.AppointmentItem) error {
+	item.Name = strings.TrimSpace(item.Name)
+	if item.Name == "" {
+		return errors.New("la descripcion de la linea es requerida")
+	}
+
+	if !domain.ValidCurrency(item.UnitPrice.Currency) {
+		return fmt.Errorf("%w: moneda invalida", domain.ErrorInvalidInput)
+	}
+
+	if err := item.Tax.Validate(); err != nil {
+		return err
+	}
+
+	// El precio de una linea libre es el cobrado, no hay precio de lista
+	item.ListPrice = domain.Money{}
+	item.OverrideReason = ""
+	return nil
+}
+
+// keepItems reutiliza en items la linea existente del mismo producto para
+// que editar la cita no cambie lo ya acordado. Si la linea trae un precio
+// nuevo solo se reutiliza la linea, no su precio.
+func keepItems(existing, items []domain.AppointmentItem) {
+	used := map[uint]bool{}
+	for i := range items {
+		item := &items[i]
+		if item.IsCustom() || item.Name != "" {
+			continue
+		}
+
+		for _, old := range existing {
+			if old.IsCustom() || *old.ProductID != *item.ProductID || used[old.ID] {
+				continue
+			}
+			used[old.ID] = true
+			item.ID = old.ID
+			item.AppointmentID = old.AppointmentID
+			item.CreatedAt = old.CreatedAt
+			if item.UnitPrice.Currency == "" && item.OverrideReason == "" {
+				item.Name = old.Name
+				item.Category = old.Category
+				item.Tax = old.Tax
+				item.UnitPrice = old.UnitPrice
+				item.ListPrice = old.ListPrice
+				item.OverrideReason = old.OverrideReason
+			}
+			break
+		}
+	}
+}
+
+// requiredDuration suma la duracion y el tiempo de limpieza de los productos
+func requiredDuration(products []domain.Product) time.Duration {
+	var minutes int
+	for _, prod := range products {
+		minutes += prod.DurationMinutes + prod.BufferMinutes
+	}
+
+	return time.Duration(minutes) * time.Minute
+}
+
+// resolveEndTime calcula la hora de fin si no se envio, o valida que cubra
+// la duracion de los servicios seleccionados
+func (s *AppointmentService) resolveEndTime(appt *domain.Appointment, products []domain.Product) error {
+	required := requiredDuration(products)
+
+	if appt.EndTime.IsZero() {
+		if required <= 0 {
+			return errors.New("no se puede calcular la hora de fin sin servicios con duracion")
+		}
+		appt.EndTime = appt.StartTime.Add(required)
+		return nil
+	}
+
+	if !appt.EndTime.After(appt.StartTime) {
+		return errors.New("la hora de fin debe ser posterior a la de inicio")
+	}
+
+	if appt.EndTime.Sub(appt.StartTime) < required {
+		return fmt.Errorf("la duracion del turno es menor a la requerida por los servicios (%d minutos)", int(required.Minutes()))
+	}
+
+	return nil
+}
+
+func (s *AppointmentService) validateClient(ctx context.Context, clientID uint) error {
+	client, err := s.clientRepo.GetById(ctx, clientID)
 	if err != nil {
+		if err == domain.ErrorNotFound {
+			return errors.New("cliente no encontrado")
+		}
 		return err
 	}
 
-	return s.apptRepo.Delete(ctx, id)
+	if client.ErasedAt != nil {
+		return errors.New("el cliente solicito la eliminacion de sus datos")
+	}
+
+	return nil
 }
 
-func (s *AppointmentService) GetTotalPrice(ctx context.Context, id uint) (float64, error) {
-	appt, err := s.apptRepo.GetById(ctx, id)
+func (s *AppointmentService) validateBarber(ctx context.Context, barberID uint) error {
+	barber, err := s.barberRepo.GetById(ctx, barberID)
 	if err != nil {
-		return 0, err
+		if err == domain.ErrorNotFound {
+			return fmt.Errorf("barbero %d no encontrado: %w", barberID, err)
+		}
+		return err
 	}
 
-	var total float64
-	for _, product := range appt.Products {
-		total += product.Price
+	if !barber.Active {
+		return errors.New("el barbero no esta activo")
 	}
 
-	return total, nil
+	return nil
+}
+
+// checkHolds rechaza el turno si su horario esta reservado temporalmente
+func (s *AppointmentService) checkHolds(ctx context.Context, appt *domain.Appointment) error {
+	if s.holder == nil {
+		return nil
+	}
+
+	held, err := s.holder.HeldSlots(ctx, appt.BarberID, appt.StartTime, appt.EndTime)
+	if err != nil {
+		return err
+	}
+
+	if len(held) > 0 {
+		return errors.New("el horario esta reservado temporalmente para la lista de espera")
+	}
+
+	return nil
+}
+
+// shiftAppointment copia la cita moviendola a otra hora de inicio y
+// conservando su duracion, si se habia indicado una hora de fin
+func shiftAppointment(appt *domain.Appointment, start time.Time) *domain.Appointment {
+	shifted := *appt
+	shifted.StartTime = start
+	if !appt.EndTime.IsZero() {
+		shifted.EndTime = start.Add(appt.EndTime.Sub(appt.StartTime))
+	}
+
+	// Cada cita tiene sus propias lineas
+	shifted.Items = make([]domain.AppointmentItem, len(appt.Items))
+	for i, item := range appt.Items {
+		item.ID = 0
+		item.AppointmentID = 0
+		shifted.Items[i] = item
+	}
+
+	return &shifted
+}
+
+// conflicts indica si el turno ocupa el horario de otra cita activa del mismo barbero
+func (s *AppointmentService) conflicts(appt, existing *domain.Appointment) bool {
+	if (existing.ID != 0 && existing.ID == appt.ID) || existing.Status == domain.StatusCancelled {
+		return false
+	}
+
+	return existing.BarberID == appt.BarberID && s.appointmentsOverlap(appt, existing)
+}
+
+func canTransition(from, to domain.AppointmentStatus) bool {
+	for _, allowed := range appointmentTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (s *AppointmentService) appointmentsOverlap(appt1, appt2 *domain.Appointment) bool {
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"slices"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+)
+
+type taxKey struct {
+	rate   int
+	exempt bool
+}
+
+// appointmentTaxes calcula el impuesto de la cita por tarifa. Cada descuento
+// se reparte entre las lineas a las que aplica en proporcion a su importe,
+// sin perder centavos, y el impuesto se calcula sobre lo que queda de cada
+// linea. Devuelve tambien el impuesto que se suma al total por los precios
+// que no lo incluyen.
+func appointmentTaxes(items []domain.AppointmentItem, discounts []domain.TotalLine) ([]domain.TaxLine, domain.Money) {
+	amounts := make([]domain.Money, len(items))
+	for i, item := range items {
+		amounts[i] = item.Total()
+	}
+
+	for _, discount := range discounts {
+		weights := make([]int64, len(items))
+		var available int64
+		for i, item := range items {
+			if len(discount.ItemIDs) == 0 || slices.Contains(discount.ItemIDs, item.ID) {
+				weights[i] = max(amounts[i].Cents, 0)
+				available += weights[i]
+			}
+		}
+
+		// El descuento no puede dejar lineas en negativo
+		amount := discount.Amount.Min(domain.NewMoney(available, discount.Amount.Currency))
+		for i, part := range amount.Allocate(weights) {
+			amounts[i] = amounts[i].Sub(part)
+		}
+	}
+
+	// Importes por tarifa, separando los precios con y sin impuesto incluido
+	included := map[taxKey]domain.Money{}
+	excluded := map[taxKey]domain.Money{}
+	var keys []taxKey
+	for i, item := range items {
+		key := taxKey{item.Tax.Rate, item.Tax.Exempt}
+		if _, ok := included[key]; !ok {
+			keys = append(keys, key)
+			included[key] = domain.Money{}
+			excluded[key] = domain.Money{}
+		}
+
+		if item.Tax.Included {
+			included[key] = included[key].Add(amounts[i])
+		} else {
+			excluded[key] = excluded[key].Add(amounts[i])
+		}
+	}
+
+	// Las tarifas de menor a mayor y los exentos al final
+	slices.SortFunc(keys, func(a, b taxKey) int {
+		if a.exempt != b.exempt {
+			if a.exempt {
+				return 1
+			}
+			return -1
+		}
+		return a.rate - b.rate
+	})
+
+	taxes := []domain.TaxLine{}
+	var added domain.Money
+	for _, key := range keys {
+		inBase, inTax := domain.Tax{Rate: key.rate, Exempt: key.exempt, Included: true}.Split(included[key])
+		exBase, exTax := domain.Tax{Rate: key.rate, Exempt: key.exempt}.Split(excluded[key])
+
+		taxes = append(taxes, domain.TaxLine{
+			Rate:   key.rate,
+			Exempt: key.exempt,
+			Base:   inBase.Add(exBase),
+			Tax:    inTax.Add(exTax),
+		})
+		added = added.Add(exTax)
+	}
+
+	return taxes, added
+}
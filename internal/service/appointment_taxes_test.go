@@ -0,0 +1,139 @@
+package service
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+)
+
+func cop(cents int64) domain.Money {
+	return domain.NewMoney(cents, "COP")
+}
+
+func taxItem(id uint, cents int64, quantity int, tax domain.Tax) domain.AppointmentItem {
+	return domain.AppointmentItem{ID: id, UnitPrice: cop(cents), Quantity: quantity, Tax: tax}
+}
+
+func TestAppointmentTaxes(t *testing.T) {
+	included := domain.Tax{Rate: 1900, Included: true}
+	excluded := domain.Tax{Rate: 1900}
+	exempt := domain.Tax{Exempt: true}
+
+	tests := []struct {
+		name      string
+		items     []domain.AppointmentItem
+		discounts []domain.TotalLine
+		want      []domain.TaxLine
+		wantAdded domain.Money
+	}{
+		{
+			name:      "sin lineas",
+			want:      []domain.TaxLine{},
+			wantAdded: domain.Money{},
+		},
+		{
+			name:      "impuesto incluido",
+			items:     []domain.AppointmentItem{taxItem(1, 11900, 1, included)},
+			want:      []domain.TaxLine{{Rate: 1900, Base: cop(10000), Tax: cop(1900)}},
+			wantAdded: domain.Money{},
+		},
+		{
+			name:      "impuesto sin incluir",
+			items:     []domain.AppointmentItem{taxItem(1, 10000, 1, excluded)},
+			want:      []domain.TaxLine{{Rate: 1900, Base: cop(10000), Tax: cop(1900)}},
+			wantAdded: cop(1900),
+		},
+		{
+			name:      "cantidad",
+			items:     []domain.AppointmentItem{taxItem(1, 5000, 3, excluded)},
+			want:      []domain.TaxLine{{Rate: 1900, Base: cop(15000), Tax: cop(2850)}},
+			wantAdded: cop(2850),
+		},
+		{
+			name: "incluido y sin incluir en la misma tarifa",
+			items: []domain.AppointmentItem{
+				taxItem(1, 11900, 1, included),
+				taxItem(2, 10000, 1, excluded),
+			},
+			want:      []domain.TaxLine{{Rate: 1900, Base: cop(20000), Tax: cop(3800)}},
+			wantAdded: cop(1900),
+		},
+		{
+			name:      "medio centavo sin incluir",
+			items:     []domain.AppointmentItem{taxItem(1, 50, 1, excluded)},
+			want:      []domain.TaxLine{{Rate: 1900, Base: cop(50), Tax: cop(10)}},
+			wantAdded: cop(10),
+		},
+		{
+			name:      "medio centavo incluido",
+			items:     []domain.AppointmentItem{taxItem(1, 3, 1, domain.Tax{Rate: 10000, Included: true})},
+			want:      []domain.TaxLine{{Rate: 10000, Base: cop(2), Tax: cop(1)}},
+			wantAdded: domain.Money{},
+		},
+		{
+			name: "tarifas ordenadas y exentos al final",
+			items: []domain.AppointmentItem{
+				taxItem(1, 5000, 1, exempt),
+				taxItem(2, 11900, 1, included),
+				taxItem(3, 2000, 1, domain.Tax{Rate: 500}),
+				taxItem(4, 3000, 1, domain.Tax{}),
+			},
+			want: []domain.TaxLine{
+				{Rate: 0, Base: cop(3000), Tax: cop(0)},
+				{Rate: 500, Base: cop(2000), Tax: cop(100)},
+				{Rate: 1900, Base: cop(10000), Tax: cop(1900)},
+				{Exempt: true, Base: cop(5000), Tax: cop(0)},
+			},
+			wantAdded: cop(100),
+		},
+		{
+			name: "descuento repartido entre todas las lineas",
+			items: []domain.AppointmentItem{
+				taxItem(1, 11900, 1, included),
+				taxItem(2, 10000, 1, exempt),
+			},
+			discounts: []domain.TotalLine{{Amount: cop(2190)}},
+			want: []domain.TaxLine{
+				{Rate: 1900, Base: cop(9000), Tax: cop(1710)},
+				{Exempt: true, Base: cop(9000), Tax: cop(0)},
+			},
+			wantAdded: cop(0),
+		},
+		{
+			name: "descuento con resto de centavos",
+			items: []domain.AppointmentItem{
+				taxItem(1, 100, 1, excluded),
+				taxItem(2, 100, 1, exempt),
+				taxItem(3, 100, 1, exempt),
+			},
+			discounts: []domain.TotalLine{{Amount: cop(100)}},
+			want: []domain.TaxLine{
+				{Rate: 1900, Base: cop(66), Tax: cop(13)},
+				{Exempt: true, Base: cop(134), Tax: cop(0)},
+			},
+			wantAdded: cop(13),
+		},
+		{
+			name: "descuento acotado a una linea no supera su importe",
+			items: []domain.AppointmentItem{
+				taxItem(1, 10000, 1, excluded),
+				taxItem(2, 5000, 1, excluded),
+			},
+			discounts: []domain.TotalLine{{Amount: cop(8000), ItemIDs: []uint{2}}},
+			want:      []domain.TaxLine{{Rate: 1900, Base: cop(10000), Tax: cop(1900)}},
+			wantAdded: cop(1900),
+		},
+	}
+
+	for _, tt := range tests {
+		got, added := appointmentTaxes(tt.items, tt.discounts)
+
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: impuestos = %+v, se esperaba %+v", tt.name, got, tt.want)
+		}
+		if added != tt.wantAdded {
+			t.Errorf("%s: impuesto sumado = %v, se esperaba %v", tt.name, added, tt.wantAdded)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+)
+
+// AvailabilityConfig define la grilla sobre la que se ofrecen los turnos.
+// El horario de atencion se toma del calendario de la barberia.
+type AvailabilityConfig struct {
+	SlotInterval time.Duration
+}
+
+type AvailabilityService struct {
+	appts *AppointmentService
+	cfg   AvailabilityConfig
+}
+
+func NewAvailabilityService(a *AppointmentService, cfg AvailabilityConfig) *AvailabilityService {
+	return &AvailabilityService{a, cfg}
+}
+
+// Location es la zona horaria de la barberia, en la que se interpretan las
+// fechas de busqueda
+func (s *AvailabilityService) Location() *time.Location {
+	return s.appts.calendar.Location()
+}
+
+// FindSlots devuelve los horarios libres de la fecha para los productos
+// indicados. Si barberID es cero se buscan turnos con todos los barberos activos.
+func (s *AvailabilityService) FindSlots(ctx context.Context, date time.Time, productIDs []uint, barberID uint) ([]domain.AvailableSlot, error) {
+	if s.cfg.SlotInterval <= 0 {
+		return nil, errors.New("el intervalo de turnos debe ser mayor a cero")
+	}
+
+	// 1. Calcular la duracion requerida por los servicios
+	products := make([]domain.Product, len(productIDs))
+	for i, id := range productIDs {
+		products[i] = domain.Product{ID: id}
+	}
+
+	if err := s.appts.loadProducts(ctx, products); err != nil {
+		return nil, err
+	}
+
+	required := requiredDuration(products)
+	if required <= 0 {
+		return nil, errors.New("seleccione al menos un servicio con duracion")
+	}
+
+	// 2. Consultar el horario de atencion del dia
+	opening, closing, isOpen, err := s.appts.calendar.OpeningHours(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := []domain.AvailableSlot{}
+	if !isOpen {
+		return slots, nil
+	}
+
+	// 3. Determinar los barberos a consultar
+	barbers, err := s.barbers(ctx, barberID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Recorrer la grilla del dia descartando horarios ocupados
+	now := time.Now()
+	for _, barber := range barbers {
+		existingAppts, err := s.appts.apptRepo.FindOverlapping(ctx, barber.ID, opening, closing, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		// Los horarios reservados para la lista de espera tampoco estan libres
+		if s.appts.holder != nil {
+			held, err := s.appts.holder.HeldSlots(ctx, barber.ID, opening, closing)
+			if err != nil {
+				return nil, err
+			}
+			for _, slot := range held {
+				existingAppts = append(existingAppts, domain.Appointment{
+					BarberID:  slot.BarberID,
+					StartTime: slot.StartTime,
+					EndTime:   slot.EndTime,
+					Status:    domain.StatusScheduled,
+				})
+			}
+		}
+
+		for start := opening; !start.Add(required).After(closing); start = start.Add(s.cfg.SlotInterval) {
+			if start.Before(now) {
+				continue
+			}
+
+			candidate := &domain.Appointment{
+				BarberID:  barber.ID,
+				StartTime: start,
+				EndTime:   start.Add(required),
+			}
+
+			if s.isFree(candidate, existingAppts) {
+				slots = append(slots, domain.AvailableSlot{
+					BarberID:  barber.ID,
+					StartTime: candidate.StartTime,
+					EndTime:   candidate.EndTime,
+				})
+			}
+		}
+	}
+
+	return slots, nil
+}
+
+func (s *AvailabilityService) barbers(ctx context.Context, barberID uint) ([]domain.Barber, error) {
+	if barberID != 0 {
+		if err := s.appts.validateBarber(ctx, barberID); err != nil {
+			return nil, err
+		}
+		return []domain.Barber{{ID: barberID}}, nil
+	}
+
+	all, err := s.appts.barberRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var active []domain.Barber
+	for _, barber := range all {
+		if barber.Active {
+			active = append(active, barber)
+		}
+	}
+
+	return active, nil
+}
+
+func (s *AvailabilityService) isFree(candidate *domain.Appointment, existingAppts []domain.Appointment) bool {
+	for _, existing := range existingAppts {
+		if s.appts.conflicts(candidate, &existing) {
+			return false
+		}
+	}
+
+	return true
+}
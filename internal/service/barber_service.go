@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+)
+
+type BarberService struct {
+	barberRepo domain.BarberRepo
+	apptRepo   domain.AppointmentRepo
+}
+
+func NewBarberService(b domain.BarberRepo, a domain.AppointmentRepo) *BarberService {
+	return &BarberService{b, a}
+}
+
+func (s *BarberService) Create(ctx context.Context, barber *domain.Barber) error {
+	// Validaciones basicas
+	if barber.Name == "" {
+		return errors.New("el nombre del barbero es requerido")
+	}
+
+	// verificar que no exista un barbero con el mismo nombre
+	existing, err := s.barberRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, existingBarber := range existing {
+		if existingBarber.Name == barber.Name {
+			return errors.New("ya existe un barbero con ese nombre")
+		}
+	}
+
+	return s.barberRepo.Create(ctx, barber)
+}
+
+func (s *BarberService) GetByID(ctx context.Context, id uint) (*domain.Barber, error) {
+	return s.barberRepo.GetById(ctx, id)
+}
+
+func (s *BarberService) ListAll(ctx context.Context) ([]domain.Barber, error) {
+	return s.barberRepo.List(ctx)
+}
+
+// Update modifica el barbero. Con active en nil se conserva si esta activo.
+func (s *BarberService) Update(ctx context.Context, id uint, updatedBarber *domain.Barber, active *bool) error {
+	// Validaciones basicas
+	if updatedBarber.Name == "" {
+		return errors.New("el nombre del barbero es requerido")
+	}
+
+	// verificar que el barbero existe
+	existing, err := s.barberRepo.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// verificar que no exista otro barbero con el mismo nombre
+	allBarbers, err := s.barberRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, existingBarber := range allBarbers {
+		if existingBarber.Name == updatedBarber.Name && existingBarber.ID != id {
+			return errors.New("ya existe un barbero con ese nombre")
+		}
+	}
+
+	// mantener el id original
+	updatedBarber.ID = existing.ID
+	updatedBarber.CreatedAt = existing.CreatedAt
+
+	updatedBarber.Active = existing.Active
+	if active != nil {
+		updatedBarber.Active = *active
+	}
+
+	return s.barberRepo.Update(ctx, updatedBarber)
+}
+
+func (s *BarberService) Delete(ctx context.Context, id uint) error {
+	// Verificar que el barbero existe
+	_, err := s.barberRepo.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// Las citas conservan el barbero que atendio; se puede desactivar
+	appts, _, err := s.apptRepo.List(ctx, domain.AppointmentListOptions{BarberID: id, Limit: 1})
+	if err != nil {
+		return err
+	}
+
+	if len(appts) > 0 {
+		return domain.ErrorBarberHasAppts
+	}
+
+	return s.barberRepo.Delete(ctx, id)
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+)
+
+type CalendarService struct {
+	calendarRepo domain.CalendarRepo
+	location     *time.Location
+}
+
+// NewCalendarService crea el servicio con la zona horaria de la barberia, en
+// la que se interpretan los dias y el horario de atencion
+func NewCalendarService(c domain.CalendarRepo, loc *time.Location) *CalendarService {
+	if loc == nil {
+		loc = time.Local
+	}
+	return &CalendarService{c, loc}
+}
+
+// Location es la zona horaria de la barberia
+func (s *CalendarService) Location() *time.Location {
+	return s.location
+}
+
+func (s *CalendarService) ListWeeklyHours(ctx context.Context) ([]domain.BusinessHours, error) {
+	return s.calendarRepo.ListWeeklyHours(ctx)
+}
+
+func (s *CalendarService) SetWeeklyHours(ctx context.Context, hours *domain.BusinessHours) error {
+	if hours.Weekday < 0 || hours.Weekday > 6 {
+		return errors.New("el dia de la semana debe estar entre 0 (domingo) y 6 (sabado)")
+	}
+
+	if hours.Closed {
+		hours.OpenTime = ""
+		hours.CloseTime = ""
+	} else if err := validateHoursRange(hours.OpenTime, hours.CloseTime); err != nil {
+		return err
+	}
+
+	return s.calendarRepo.SaveWeeklyHours(ctx, hours)
+}
+
+func (s *CalendarService) CreateException(ctx context.Context, exc *domain.CalendarException) error {
+	if err := validateException(exc); err != nil {
+		return err
+	}
+
+	// verificar que no exista otra excepcion para la misma fecha
+	if _, err := s.calendarRepo.GetExceptionByDate(ctx, exc.Date); err == nil {
+		return errors.New("ya existe una excepcion para esa fecha")
+	} else if err != domain.ErrorNotFound {
+		return err
+	}
+
+	return s.calendarRepo.CreateException(ctx, exc)
+}
+
+func (s *CalendarService) GetException(ctx context.Context, id uint) (*domain.CalendarException, error) {
+	return s.calendarRepo.GetExceptionById(ctx, id)
+}
+
+func (s *CalendarService) ListExceptions(ctx context.Context, from, to time.Time) ([]domain.CalendarException, error) {
+	return s.calendarRepo.ListExceptions(ctx, from, to)
+}
+
+func (s *CalendarService) UpdateException(ctx context.Context, id uint, updatedExc *domain.CalendarException) error {
+	if err := validateException(updatedExc); err != nil {
+		return err
+	}
+
+	// verificar que la excepcion existe
+	existing, err := s.calendarRepo.GetExceptionById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// verificar que no exista otra excepcion para la misma fecha
+	sameDate, err := s.calendarRepo.GetExceptionByDate(ctx, updatedExc.Date)
+	if err == nil && sameDate.ID != id {
+		return errors.New("ya existe una excepcion para esa fecha")
+	} else if err != nil && err != domain.ErrorNotFound {
+		return err
+	}
+
+	// mantener el id original
+	updatedExc.ID = existing.ID
+	updatedExc.CreatedAt = existing.CreatedAt
+
+	return s.calendarRepo.UpdateException(ctx, updatedExc)
+}
+
+func (s *CalendarService) DeleteException(ctx context.Context, id uint) error {
+	// Verificar que la excepcion existe
+	_, err := s.calendarRepo.GetExceptionById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.calendarRepo.DeleteException(ctx, id)
+}
+
+// OpeningHours devuelve la apertura y el cierre de la barberia en la fecha
+// indicada, tomada en la zona horaria de la barberia sin importar el offset
+// con que llegue. isOpen es falso si ese dia la barberia no atiende.
+func (s *CalendarService) OpeningHours(ctx context.Context, date time.Time) (opening, closing time.Time, isOpen bool, err error) {
+	date = date.In(s.location)
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, s.location)
+
+	openTime, closeTime := "", ""
+
+	// Las excepciones tienen prioridad sobre el horario semanal
+	exc, err := s.calendarRepo.GetExceptionByDate(ctx, day)
+	switch {
+	case err == nil:
+		if exc.Kind != domain.CalendarOverride {
+			return day, day, false, nil
+		}
+		openTime, closeTime = exc.OpenTime, exc.CloseTime
+	case err == domain.ErrorNotFound:
+		hours, err := s.calendarRepo.GetWeeklyHours(ctx, int(day.Weekday()))
+		if err == domain.ErrorNotFound {
+			return day, day, false, nil
+		}
+		if err != nil {
+			return day, day, false, err
+		}
+		if hours.Closed {
+			return day, day, false, nil
+		}
+		openTime, closeTime = hours.OpenTime, hours.CloseTime
+	default:
+		return day, day, false, err
+	}
+
+	openOffset, err := ParseClock(openTime)
+	if err != nil {
+		return day, day, false, err
+	}
+	closeOffset, err := ParseClock(closeTime)
+	if err != nil {
+		return day, day, false, err
+	}
+
+	return atClock(day, openOffset), atClock(day, closeOffset), true, nil
+}
+
+// atClock devuelve la hora de reloj indicada en el dia. Se arma con time.Date
+// y no sumando a la medianoche para que los dias con cambio de horario no
+// corran la apertura ni el cierre.
+func atClock(day time.Time, clock time.Duration) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(),
+		int(clock/time.Hour), int(clock%time.Hour/time.Minute), 0, 0, day.Location())
+}
+
+// ValidateWithinHours verifica que el turno este dentro del horario de atencion
+func (s *CalendarService) ValidateWithinHours(ctx context.Context, start, end time.Time) error {
+	opening, closing, isOpen, err := s.OpeningHours(ctx, start)
+	if err != nil {
+		return err
+	}
+
+	if !isOpen {
+		return errors.New("la barberia esta cerrada ese dia")
+	}
+
+	if start.Before(opening) || end.After(closing) {
+		return fmt.Errorf("el turno esta fuera del horario de atencion (%s - %s)",
+			opening.Format("15:04"), closing.Format("15:04"))
+	}
+
+	return nil
+}
+
+func validateException(exc *domain.CalendarException) error {
+	if exc.Date.IsZero() {
+		return errors.New("la fecha es requerida")
+	}
+
+	switch exc.Kind {
+	case domain.CalendarHoliday, domain.CalendarClosure:
+		exc.OpenTime = ""
+		exc.CloseTime = ""
+		return nil
+	case domain.CalendarOverride:
+		return validateHoursRange(exc.OpenTime, exc.CloseTime)
+	default:
+		return errors.New("tipo de excepcion invalido, use holiday, closure u override")
+	}
+}
+
+func validateHoursRange(openTime, closeTime string) error {
+	opening, err := ParseClock(openTime)
+	if err != nil {
+		return err
+	}
+
+	closing, err := ParseClock(closeTime)
+	if err != nil {
+		return err
+	}
+
+	if closing <= opening {
+		return errors.New("la hora de cierre debe ser posterior a la de apertura")
+	}
+
+	return nil
+}
+
+// ParseClock convierte una hora en formato HH:MM en el tiempo desde la medianoche
+func ParseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("hora invalida %q, use HH:MM", value)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
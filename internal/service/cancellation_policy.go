@@ -0,0 +1,44 @@
+package service
+
+import (
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+)
+
+// CancellationPolicy define hasta cuando se puede cancelar sin cargo y que
+// porcentaje del total de la cita se cobra por cancelar tarde o no asistir
+type CancellationPolicy struct {
+	// FreeCancelWindow es la anticipacion minima para cancelar sin cargo
+	FreeCancelWindow     time.Duration
+	LateCancelFeePercent int
+	NoShowFeePercent     int
+	// AllowLateCancel en false rechaza las cancelaciones fuera de plazo
+	AllowLateCancel bool
+}
+
+// isLate indica si cancelar la cita en now ya esta fuera del plazo gratuito
+func (p CancellationPolicy) isLate(appt *domain.Appointment, now time.Time) bool {
+	return !now.Before(appt.StartTime.Add(-p.FreeCancelWindow))
+}
+
+// newFee arma el cargo por el porcentaje indicado del total de la cita.
+// Devuelve nil si no hay nada que cobrar.
+func (p CancellationPolicy) newFee(appt *domain.Appointment, reason domain.FeeReason, percent int) *domain.Fee {
+	if percent <= 0 {
+		return nil
+	}
+
+	amount := appointmentTotal(appt).Percent(percent)
+	if !amount.IsPositive() {
+		return nil
+	}
+
+	return &domain.Fee{
+		ClientID:      appt.ClientID,
+		AppointmentID: appt.ID,
+		Reason:        reason,
+		Percent:       percent,
+		Amount:        amount,
+	}
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultDuplicateScore es el puntaje minimo para considerar dos clientes duplicados
+const DefaultDuplicateScore = 0.85
+
+// FindDuplicates compara todos los clientes y devuelve los pares probablemente
+// duplicados: mismo telefono o email normalizado, o nombres parecidos sin
+// importar tildes, mayusculas ni el orden de las palabras
+func (s *ClientService) FindDuplicates(ctx context.Context, minScore float64) ([]domain.DuplicateCandidate, error) {
+	all, err := s.clientRepo.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Los clientes anonimizados no se comparan
+	clients := make([]domain.Client, 0, len(all))
+	for _, client := range all {
+		if client.ErasedAt == nil {
+			clients = append(clients, client)
+		}
+	}
+
+	// En cada par el primero es el mas antiguo, que se sugiere como sobreviviente
+	sort.Slice(clients, func(i, j int) bool {
+		if !clients[i].CreatedAt.Equal(clients[j].CreatedAt) {
+			return clients[i].CreatedAt.Before(clients[j].CreatedAt)
+		}
+		return clients[i].ID < clients[j].ID
+	})
+
+	type normalized struct {
+		name, phone, email string
+	}
+
+	keys := make([]normalized, len(clients))
+	for i, client := range clients {
+		keys[i] = normalized{
+			name:  normalizeName(client.Name),
+			phone: normalizePhone(client.Phone),
+			email: normalizeEmail(client.Email),
+		}
+	}
+
+	candidates := []domain.DuplicateCandidate{}
+	for i := range clients {
+		for j := i + 1; j < len(clients); j++ {
+			a, b := keys[i], keys[j]
+
+			var reasons []string
+			score := nameSimilarity(a.name, b.name)
+			if score >= minScore {
+				reasons = append(reasons, "nombre similar")
+			}
+			if a.phone != "" && a.phone == b.phone {
+				reasons = append(reasons, "mismo telefono")
+				score = 1
+			}
+			if a.email != "" && a.email == b.email {
+				reasons = append(reasons, "mismo email")
+				score = 1
+			}
+
+			if len(reasons) == 0 {
+				continue
+			}
+
+			candidates = append(candidates, domain.DuplicateCandidate{
+				Client:    clients[i],
+				Duplicate: clients[j],
+				Score:     score,
+				Reasons:   reasons,
+			})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+
+	return candidates, nil
+}
+
+// Merge fusiona el duplicado en el cliente sobreviviente. Los datos de
+// contacto vacios del sobreviviente se completan con los del duplicado y las
+// notas de ambos se conservan.
+func (s *ClientService) Merge(ctx context.Context, survivorID, duplicateID uint) (*domain.Client, error) {
+	if survivorID == duplicateID {
+		return nil, errors.New("no se puede fusionar un cliente consigo mismo")
+	}
+
+	survivor, err := s.clientRepo.GetById(ctx, survivorID)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicate, err := s.clientRepo.GetById(ctx, duplicateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if survivor.ErasedAt != nil || duplicate.ErasedAt != nil {
+		return nil, errors.New("no se puede fusionar un cliente con datos eliminados")
+	}
+
+	if survivor.Phone == "" {
+		survivor.Phone = duplicate.Phone
+	}
+	if survivor.Email == "" {
+		survivor.Email = duplicate.Email
+	}
+	if survivor.Birthday == nil {
+		survivor.Birthday = duplicate.Birthday
+	}
+	if duplicate.Notes != "" {
+		if survivor.Notes != "" {
+			survivor.Notes += "\n"
+		}
+		survivor.Notes += duplicate.Notes
+	}
+
+	if err := s.clientRepo.Merge(ctx, survivor, duplicate.ID); err != nil {
+		return nil, err
+	}
+
+	return survivor, nil
+}
+
+// normalizeName quita tildes, mayusculas, signos y ordena las palabras
+func normalizeName(name string) string {
+	stripAccents := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	plain, _, err := transform.String(stripAccents, name)
+	if err != nil {
+		plain = name
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(plain), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	sort.Strings(words)
+
+	return strings.Join(words, " ")
+}
+
+// normalizePhone deja solo los digitos y quita el indicativo de Colombia
+func normalizePhone(phone string) string {
+	var digits strings.Builder
+	for _, r := range phone {
+		if unicode.IsDigit(r) {
+			digits.WriteRune(r)
+		}
+	}
+
+	normalized := digits.String()
+	if len(normalized) == 12 && strings.HasPrefix(normalized, "57") {
+		normalized = normalized[2:]
+	}
+
+	return normalized
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// nameSimilarity devuelve un valor entre 0 y 1 basado en la distancia de
+// Levenshtein entre dos nombres ya normalizados
+func nameSimilarity(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
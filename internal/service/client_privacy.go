@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+)
+
+// Export reune todo lo que se guarda del cliente: sus datos, citas con los
+// servicios tomados, lista de espera, cargos, puntos, promociones usadas,
+// pagos con tarjetas de regalo y recibos
+func (s *ClientService) Export(ctx context.Context, id uint) (*domain.ClientExport, error) {
+	client, err := s.clientRepo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	appts, _, err := s.apptRepo.List(ctx, domain.AppointmentListOptions{ClientID: id})
+	if err != nil {
+		return nil, err
+	}
+
+	waitlist, err := s.waitlistRepo.ListByClient(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	fees, err := s.feeRepo.ListByClient(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	loyalty, err := s.loyaltyRepo.ListByClient(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	promotions, err := s.promoRepo.ListRedemptionsByClient(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	vouchers, err := s.voucherRepo.ListTransactionsByClient(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	receipts, err := s.receiptRepo.ListByClient(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// El cliente ya va en la raiz de la exportacion
+	for i := range appts {
+		appts[i].Client = nil
+	}
+
+	return &domain.ClientExport{
+		ExportedAt:   time.Now(),
+		Client:       *client,
+		Appointments: appts,
+		Waitlist:     waitlist,
+		Fees:         fees,
+		Loyalty:      loyalty,
+		Promotions:   promotions,
+		Vouchers:     vouchers,
+		Receipts:     receipts,
+	}, nil
+}
+
+// Erase anonimiza los datos personales del cliente, tambien el nombre impreso
+// en sus recibos. Las citas, los cargos y los recibos se conservan, asociados
+// al cliente anonimo, para la contabilidad. No se permite mientras el cliente
+// tenga citas por atender.
+func (s *ClientService) Erase(ctx context.Context, id uint) (*domain.Client, error) {
+	client, err := s.clientRepo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.ErasedAt != nil {
+		return nil, errors.New("los datos del cliente ya fueron eliminados")
+	}
+
+	now := time.Now()
+	pending, _, err := s.apptRepo.List(ctx, domain.AppointmentListOptions{ClientID: id, From: &now})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, appt := range pending {
+		if appt.Status == domain.StatusScheduled || appt.Status == domain.StatusConfirmed {
+			return nil, fmt.Errorf("el cliente tiene citas por atender (cita %d), cancelelas antes de eliminar sus datos", appt.ID)
+		}
+	}
+
+	client.Name = fmt.Sprintf("Cliente eliminado #%d", client.ID)
+	client.Phone = ""
+	client.Email = ""
+	client.Birthday = nil
+	client.Notes = ""
+	client.ErasedAt = &now
+
+	if err := s.clientRepo.Erase(ctx, client); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
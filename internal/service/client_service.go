@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+)
+
+type ClientService struct {
+	clientRepo   domain.ClientRepo
+	apptRepo     domain.AppointmentRepo
+	feeRepo      domain.FeeRepo
+	waitlistRepo domain.WaitlistRepo
+	loyaltyRepo  domain.LoyaltyRepo
+	promoRepo    domain.PromotionRepo
+	voucherRepo  domain.VoucherRepo
+	receiptRepo  domain.ReceiptRepo
+	appts        *AppointmentService
+}
+
+func NewClientService(c domain.ClientRepo, a *AppointmentService, f domain.FeeRepo, w domain.WaitlistRepo, l domain.LoyaltyRepo, p domain.PromotionRepo, v domain.VoucherRepo, r domain.ReceiptRepo) *ClientService {
+	return &ClientService{c, a.apptRepo, f, w, l, p, v, r, a}
+}
+
+func (s *ClientService) Create(ctx context.Context, client *domain.Client) error {
+	if err := validateClient(client); err != nil {
+		return err
+	}
+
+	return s.clientRepo.Create(ctx, client)
+}
+
+func (s *ClientService) GetByID(ctx context.Context, id uint) (*domain.Client, error) {
+	return s.clientRepo.GetById(ctx, id)
+}
+
+func (s *ClientService) List(ctx context.Context, search string) ([]domain.Client, error) {
+	return s.clientRepo.List(ctx, search)
+}
+
+func (s *ClientService) Update(ctx context.Context, id uint, updatedClient *domain.Client) error {
+	if err := validateClient(updatedClient); err != nil {
+		return err
+	}
+
+	// verificar que el cliente existe
+	existing, err := s.clientRepo.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if existing.ErasedAt != nil {
+		return errors.New("no se puede modificar un cliente con datos eliminados")
+	}
+
+	// mantener el id original
+	updatedClient.ID = existing.ID
+	updatedClient.CreatedAt = existing.CreatedAt
+
+	return s.clientRepo.Update(ctx, updatedClient)
+}
+
+func (s *ClientService) Delete(ctx context.Context, id uint) error {
+	// Verificar que el cliente existe
+	_, err := s.clientRepo.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// Las citas conservan el historial del cliente
+	appts, _, err := s.apptRepo.List(ctx, domain.AppointmentListOptions{ClientID: id, Limit: 1})
+	if err != nil {
+		return err
+	}
+
+	if len(appts) > 0 {
+		return errors.New("no se puede eliminar un cliente con citas registradas")
+	}
+
+	return s.clientRepo.Delete(ctx, id)
+}
+
+func validateClient(client *domain.Client) error {
+	client.Name = strings.TrimSpace(client.Name)
+	client.Email = strings.TrimSpace(client.Email)
+	client.Phone = strings.TrimSpace(client.Phone)
+
+	if client.Name == "" {
+		return errors.New("el nombre del cliente es requerido")
+	}
+
+	if client.Email != "" {
+		if _, err := mail.ParseAddress(client.Email); err != nil {
+			return errors.New("el email del cliente es invalido")
+		}
+	}
+
+	return nil
+}
+
+// favouriteServicesLimit es la cantidad de servicios favoritos del historial
+const favouriteServicesLimit = 3
+
+// Fees devuelve los cargos por cancelacion tardia e inasistencia del cliente
+func (s *ClientService) Fees(ctx context.Context, id uint) ([]domain.Fee, error) {
+	if _, err := s.clientRepo.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.feeRepo.ListByClient(ctx, id)
+}
+
+// History devuelve las visitas pasadas del cliente, de la mas reciente a la
+// mas antigua, con el total gastado, la frecuencia y sus servicios favoritos.
+// Solo cuentan como visitas las citas completadas; el total gastado incluye
+// descuentos e impuestos.
+func (s *ClientService) History(ctx context.Context, id uint) (*domain.ClientHistory, error) {
+	if _, err := s.clientRepo.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	appts, _, err := s.apptRepo.List(ctx, domain.AppointmentListOptions{
+		ClientID: id,
+		To:       &now,
+		SortDesc: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	history := &domain.ClientHistory{
+		ClientID:          id,
+		FavouriteServices: []domain.ServiceCount{},
+		Appointments:      []domain.Appointment{},
+		TotalSpent:        domain.NewMoney(0, domain.DefaultCurrency),
+	}
+
+	counts := map[uint]*domain.ServiceCount{}
+	for _, appt := range appts {
+		if appt.Status != domain.StatusCompleted {
+			continue
+		}
+
+		total, err := s.appts.GetTotalPrice(ctx, appt.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		history.Appointments = append(history.Appointments, appt)
+		history.TotalSpent = history.TotalSpent.Add(total.Total)
+
+		for _, item := range appt.Items {
+			// Las lineas libres no son servicios del catalogo
+			if item.IsCustom() {
+				continue
+			}
+
+			productID := *item.ProductID
+			if counts[productID] == nil {
+				counts[productID] = &domain.ServiceCount{ProductID: productID, Name: item.Name}
+			}
+			counts[productID].Count += item.Quantity
+		}
+	}
+
+	history.Visits = len(history.Appointments)
+	if history.Visits == 0 {
+		return history, nil
+	}
+
+	last := history.Appointments[0]
+	history.LastVisit = &last.StartTime
+	history.LastBarber = last.Barber
+
+	// Promedio de dias entre visitas consecutivas
+	if history.Visits > 1 {
+		first := history.Appointments[history.Visits-1]
+		days := last.StartTime.Sub(first.StartTime).Hours() / 24 / float64(history.Visits-1)
+		history.AverageVisitIntervalDays = &days
+	}
+
+	for _, count := range counts {
+		history.FavouriteServices = append(history.FavouriteServices, *count)
+	}
+	sort.Slice(history.FavouriteServices, func(i, j int) bool {
+		a, b := history.FavouriteServices[i], history.FavouriteServices[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Name < b.Name
+	})
+	if len(history.FavouriteServices) > favouriteServicesLimit {
+		history.FavouriteServices = history.FavouriteServices[:favouriteServicesLimit]
+	}
+
+	return history, nil
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+)
+
+// LoyaltyConfig define cuantos puntos se ganan por cita completada y cuanto
+// descuento vale cada punto al canjearlo
+type LoyaltyConfig struct {
+	// PointsPerUnit son los puntos por cada unidad de moneda pagada
+	PointsPerUnit float64
+	// PointsPerVisit son los puntos fijos por cada visita
+	PointsPerVisit int
+	// PointValue es el descuento que vale un punto, cero deshabilita el canje
+	PointValue domain.Money
+}
+
+type LoyaltyService struct {
+	repo  domain.LoyaltyRepo
+	appts *AppointmentService
+	cfg   LoyaltyConfig
+}
+
+// NewLoyaltyService crea el servicio y lo registra en las citas para sumar
+// puntos al completarlas y descontar los canjes del total
+func NewLoyaltyService(l domain.LoyaltyRepo, a *AppointmentService, cfg LoyaltyConfig) *LoyaltyService {
+	s := &LoyaltyService{l, a, cfg}
+	a.AddStatusListener(s)
+	a.AddTotalAdjuster(s)
+	return s
+}
+
+// Balance devuelve los puntos disponibles del cliente
+func (s *LoyaltyService) Balance(ctx context.Context, clientID uint) (int, error) {
+	if _, err := s.appts.clientRepo.GetById(ctx, clientID); err != nil {
+		return 0, err
+	}
+
+	return s.repo.Balance(ctx, clientID)
+}
+
+// History devuelve los movimientos de puntos del cliente, del mas reciente al mas antiguo
+func (s *LoyaltyService) History(ctx context.Context, clientID uint) ([]domain.LoyaltyEntry, error) {
+	if _, err := s.appts.clientRepo.GetById(ctx, clientID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListByClient(ctx, clientID)
+}
+
+// Redeem canjea puntos del cliente como descuento sobre el total de la cita
+func (s *LoyaltyService) Redeem(ctx context.Context, apptID uint, points int) (*domain.LoyaltyEntry, error) {
+	if points <= 0 {
+		return nil, fmt.Errorf("%w: la cantidad de puntos debe ser mayor a cero", domain.ErrorInvalidInput)
+	}
+
+	if !s.cfg.PointValue.IsPositive() {
+		return nil, errors.New("el canje de puntos esta deshabilitado")
+	}
+
+	appt, err := s.appts.apptRepo.GetById(ctx, apptID)
+	if err != nil {
+		return nil, err
+	}
+
+	if appt.Status == domain.StatusCompleted || appt.Status == domain.StatusCancelled || appt.Status == domain.StatusNoShow {
+		return nil, fmt.Errorf("no se pueden canjear puntos en una cita en estado %s", appt.Status)
+	}
+
+	total, err := s.appts.GetTotalPrice(ctx, apptID)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := s.cfg.PointValue.Mul(points)
+	if !amount.SameCurrency(total.Due) {
+		return nil, fmt.Errorf("%w: los puntos se canjean en %s", domain.ErrorCurrencyMismatch, s.cfg.PointValue.Currency)
+	}
+
+	if amount.Cents > total.Due.Cents {
+		return nil, fmt.Errorf("%w: el descuento supera el total pendiente de la cita", domain.ErrorInvalidInput)
+	}
+
+	entry := &domain.LoyaltyEntry{
+		ClientID:      appt.ClientID,
+		AppointmentID: &appt.ID,
+		Kind:          domain.LoyaltyRedeem,
+		Points:        -points,
+		Amount:        amount,
+	}
+
+	// El pendiente se vuelve a verificar con la cita bloqueada por si hubo
+	// otro pago
+	if err := s.repo.Redeem(ctx, entry, s.appts.dueCheck(apptID, amount)); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// StatusChanged suma los puntos al completar la cita y devuelve los canjeados
+// si la cita se cancela o el cliente no asiste
+func (s *LoyaltyService) StatusChanged(ctx context.Context, appt *domain.Appointment, from domain.AppointmentStatus) error {
+	var err error
+	switch appt.Status {
+	case domain.StatusCompleted:
+		err = s.earn(ctx, appt)
+	case domain.StatusCancelled, domain.StatusNoShow:
+		err = s.refund(ctx, appt)
+	}
+
+	if err != nil {
+		return fmt.Errorf("actualizando los puntos de la cita %d: %w", appt.ID, err)
+	}
+
+	return nil
+}
+
+func (s *LoyaltyService) earn(ctx context.Context, appt *domain.Appointment) error {
+	total, err := s.appts.GetTotalPrice(ctx, appt.ID)
+	if err != nil {
+		return err
+	}
+
+	// PointsPerUnit es por unidad de moneda y el total esta en centavos
+	points := int(math.Floor(float64(total.Total.Cents)*s.cfg.PointsPerUnit/100)) + s.cfg.PointsPerVisit
+	if points <= 0 {
+		return nil
+	}
+
+	return s.repo.Create(ctx, &domain.LoyaltyEntry{
+		ClientID:      appt.ClientID,
+		AppointmentID: &appt.ID,
+		Kind:          domain.LoyaltyEarn,
+		Points:        points,
+	})
+}
+
+func (s *LoyaltyService) refund(ctx context.Context, appt *domain.Appointment) error {
+	points, amount, err := s.redeemed(ctx, appt.ID)
+	if err != nil || points <= 0 {
+		return err
+	}
+
+	return s.repo.Create(ctx, &domain.LoyaltyEntry{
+		ClientID:      appt.ClientID,
+		AppointmentID: &appt.ID,
+		Kind:          domain.LoyaltyRefund,
+		Points:        points,
+		Amount:        amount,
+	})
+}
+
+// redeemed devuelve los puntos canjeados en la cita que no fueron devueltos
+// y el descuento que representan
+func (s *LoyaltyService) redeemed(ctx context.Context, apptID uint) (int, domain.Money, error) {
+	var points int
+	var amount domain.Money
+
+	entries, err := s.repo.ListByAppointment(ctx, apptID)
+	if err != nil {
+		return 0, amount, err
+	}
+
+	for _, entry := range entries {
+		switch entry.Kind {
+		case domain.LoyaltyRedeem:
+			points -= entry.Points
+			amount = amount.Add(entry.Amount)
+		case domain.LoyaltyRefund:
+			points -= entry.Points
+			amount = amount.Sub(entry.Amount)
+		}
+	}
+
+	return points, amount, nil
+}
+
+// Adjustments informa el descuento por puntos canjeados en la cita
+func (s *LoyaltyService) Adjustments(ctx context.Context, appt *domain.Appointment) ([]domain.TotalLine, error) {
+	points, amount, err := s.redeemed(ctx, appt.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if points <= 0 {
+		return nil, nil
+	}
+
+	return []domain.TotalLine{{
+		Kind:        "loyalty",
+		Description: fmt.Sprintf("Canje de %d puntos", points),
+		Amount:      amount,
+	}}, nil
+}
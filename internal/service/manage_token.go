@@ -0,0 +1,85 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+)
+
+// ManageTokens firma y verifica los tokens con los que un cliente administra
+// su propia cita. El token contiene el ID de la cita, su vencimiento y la
+// version del token guardada en la cita, firmados con HMAC-SHA256, por lo que
+// no puede adivinarse ni alterarse. Emitir un token con otra version revoca
+// los anteriores.
+type ManageTokens struct {
+	secret []byte
+}
+
+func NewManageTokens(secret string) *ManageTokens {
+	return &ManageTokens{[]byte(secret)}
+}
+
+// Sign genera el token de la cita valido hasta expiresAt
+func (t *ManageTokens) Sign(apptID, version uint, expiresAt time.Time) string {
+	payload := fmt.Sprintf("%d.%d.%d", apptID, expiresAt.Unix(), version)
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(t.mac(payload))
+}
+
+// Verify valida la firma y el vencimiento del token y devuelve el ID de la
+// cita y la version del token
+func (t *ManageTokens) Verify(token string) (uint, uint, error) {
+	encodedPayload, encodedMac, ok := strings.Cut(token, ".")
+	if !ok {
+		return 0, 0, domain.ErrorInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return 0, 0, domain.ErrorInvalidToken
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMac)
+	if err != nil || !hmac.Equal(mac, t.mac(string(payload))) {
+		return 0, 0, domain.ErrorInvalidToken
+	}
+
+	// El contenido es ID.vencimiento.version
+	parts := strings.Split(string(payload), ".")
+	if len(parts) != 3 {
+		return 0, 0, domain.ErrorInvalidToken
+	}
+
+	id, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return 0, 0, domain.ErrorInvalidToken
+	}
+
+	exp, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, domain.ErrorInvalidToken
+	}
+
+	version, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		return 0, 0, domain.ErrorInvalidToken
+	}
+
+	if time.Now().After(time.Unix(exp, 0)) {
+		return 0, 0, domain.ErrorInvalidToken
+	}
+
+	return uint(id), uint(version), nil
+}
+
+func (t *ManageTokens) mac(payload string) []byte {
+	h := hmac.New(sha256.New, t.secret)
+	h.Write([]byte(payload))
+	return h.Sum(nil)
+}
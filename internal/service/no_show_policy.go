@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+)
+
+// NoShowAction es lo que se exige a un cliente que supera el limite de inasistencias
+type NoShowAction string
+
+const (
+	NoShowBlock   NoShowAction = "block"
+	NoShowDeposit NoShowAction = "deposit"
+)
+
+// NoShowRule restringe las reservas de los clientes con inasistencias
+// repetidas, ej: 3 inasistencias en 90 dias exigen una sena del 50%.
+// Con Limit en cero no se aplica ninguna restriccion.
+type NoShowRule struct {
+	Limit          int
+	Window         time.Duration
+	Action         NoShowAction
+	DepositPercent int
+}
+
+type noShowOverrideKey struct{}
+
+// SetNoShowRule configura la restriccion por inasistencias que se aplica al
+// agendar y al reprogramar con el enlace de la cita
+func (s *AppointmentService) SetNoShowRule(rule NoShowRule) {
+	s.noShowRule = rule
+}
+
+// WithNoShowOverride marca una reserva hecha por el personal que puede agendar
+// a un cliente bloqueado por inasistencias. La sena se exige igual.
+func WithNoShowOverride(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noShowOverrideKey{}, true)
+}
+
+// applyNoShowRule rechaza la reserva o le exige una sena segun las
+// inasistencias recientes del cliente. Una sena ya pagada no se modifica.
+func (s *AppointmentService) applyNoShowRule(ctx context.Context, appt *domain.Appointment) error {
+	rule := s.noShowRule
+	if rule.Limit <= 0 {
+		return nil
+	}
+
+	count, err := s.apptRepo.CountByClientStatus(ctx, appt.ClientID, domain.StatusNoShow, time.Now().Add(-rule.Window))
+	if err != nil {
+		return err
+	}
+
+	if count < int64(rule.Limit) {
+		return nil
+	}
+
+	switch rule.Action {
+	case NoShowDeposit:
+		if appt.DepositPaidAt == nil {
+			appt.Deposit = appointmentTotal(appt).Percent(rule.DepositPercent)
+		}
+		return nil
+	default:
+		if override, _ := ctx.Value(noShowOverrideKey{}).(bool); override {
+			return nil
+		}
+		return fmt.Errorf("%w: %d inasistencias recientes", domain.ErrorBookingBlocked, count)
+	}
+}
+
+// PayDeposit registra el pago de la sena de la cita
+func (s *AppointmentService) PayDeposit(ctx context.Context, id uint) (*domain.Appointment, error) {
+	appt, err := s.apptRepo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !appt.Deposit.IsPositive() {
+		return nil, fmt.Errorf("%w: la cita no requiere sena", domain.ErrorInvalidInput)
+	}
+
+	if appt.DepositPaidAt != nil {
+		return nil, fmt.Errorf("%w: la sena ya fue pagada", domain.ErrorInvalidInput)
+	}
+
+	now := time.Now()
+	appt.DepositPaidAt = &now
+	if err := s.apptRepo.Update(ctx, appt); err != nil {
+		return nil, err
+	}
+
+	return appt, nil
+}
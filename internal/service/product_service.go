@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/alexnt4/barber-api/internal/domain"
 )
@@ -21,12 +23,22 @@ func (s *ProductService) Create(ctx context.Context, prod *domain.Product) error
 		return errors.New("el nombre del producto es requerido")
 	}
 
-	if prod.Price <= 0 {
-		return errors.New("el precio debe ser mayor a cero")
+	prod.Category = strings.TrimSpace(prod.Category)
+
+	if err := validatePrice(&prod.Price); err != nil {
+		return err
+	}
+
+	if err := prod.Tax.Validate(); err != nil {
+		return err
+	}
+
+	if prod.DurationMinutes < 0 || prod.BufferMinutes < 0 {
+		return errors.New("la duracion y el tiempo de limpieza no pueden ser negativos")
 	}
 
 	// verificar que no exista un producto con el mismo nombre
-	existing, err := s.prodRepo.List(ctx)
+	existing, _, err := s.prodRepo.List(ctx, domain.ProductListOptions{})
 	if err != nil {
 		return err
 	}
@@ -44,8 +56,8 @@ func (s *ProductService) GetByID(ctx context.Context, id uint) (*domain.Product,
 	return s.prodRepo.GetById(ctx, id)
 }
 
-func (s *ProductService) ListAll(ctx context.Context) ([]domain.Product, error) {
-	return s.prodRepo.List(ctx)
+func (s *ProductService) List(ctx context.Context, opts domain.ProductListOptions) ([]domain.Product, string, error) {
+	return s.prodRepo.List(ctx, opts)
 }
 
 func (s *ProductService) Update(ctx context.Context, id uint, updatedProd *domain.Product) error {
@@ -54,8 +66,18 @@ func (s *ProductService) Update(ctx context.Context, id uint, updatedProd *domai
 		return errors.New("el precio del producto es requerido")
 	}
 
-	if updatedProd.Price <= 0 {
-		return errors.New("el precio debe ser mayor a cero")
+	updatedProd.Category = strings.TrimSpace(updatedProd.Category)
+
+	if err := validatePrice(&updatedProd.Price); err != nil {
+		return err
+	}
+
+	if err := updatedProd.Tax.Validate(); err != nil {
+		return err
+	}
+
+	if updatedProd.DurationMinutes < 0 || updatedProd.BufferMinutes < 0 {
+		return errors.New("la duracion y el tiempo de limpieza no pueden ser negativos")
 	}
 
 	// verificar que el producto existe
@@ -65,7 +87,7 @@ func (s *ProductService) Update(ctx context.Context, id uint, updatedProd *domai
 	}
 
 	// verificar que no exista otro producto con el mismo nombre
-	allProducts, err := s.prodRepo.List(ctx)
+	allProducts, _, err := s.prodRepo.List(ctx, domain.ProductListOptions{})
 	if err != nil {
 		return nil
 	}
@@ -91,3 +113,22 @@ func (s *ProductService) Delete(ctx context.Context, id uint) error {
 
 	return s.prodRepo.Delete(ctx, id)
 }
+
+// validatePrice exige un precio positivo y completa la moneda de la barberia
+// si no se indico
+func validatePrice(price *domain.Money) error {
+	if !price.IsPositive() {
+		return errors.New("el precio debe ser mayor a cero")
+	}
+
+	price.Currency = strings.ToUpper(strings.TrimSpace(price.Currency))
+	if price.Currency == "" {
+		price.Currency = domain.DefaultCurrency
+	}
+
+	if !domain.ValidCurrency(price.Currency) {
+		return fmt.Errorf("%w: moneda invalida %q, use un codigo ISO 4217 como COP", domain.ErrorInvalidInput, price.Currency)
+	}
+
+	return nil
+}
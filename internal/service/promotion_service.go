@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+)
+
+type PromotionService struct {
+	repo  domain.PromotionRepo
+	appts *AppointmentService
+}
+
+// NewPromotionService crea el servicio y lo registra en las citas para
+// mostrar el descuento en el total y liberar el uso de las citas canceladas
+func NewPromotionService(p domain.PromotionRepo, a *AppointmentService) *PromotionService {
+	s := &PromotionService{p, a}
+	a.AddStatusListener(s)
+	a.AddTotalAdjuster(s)
+	return s
+}
+
+func (s *PromotionService) Create(ctx context.Context, promo *domain.Promotion) error {
+	promo.Code = normalizePromoCode(promo.Code)
+	if promo.Code == "" {
+		return fmt.Errorf("%w: el codigo de la promocion es requerido", domain.ErrorInvalidInput)
+	}
+
+	promo.Description = strings.TrimSpace(promo.Description)
+	promo.Category = strings.TrimSpace(promo.Category)
+
+	switch promo.Kind {
+	case domain.DiscountPercent:
+		if promo.Percent <= 0 || promo.Percent > 100 {
+			return fmt.Errorf("%w: el porcentaje debe estar entre 1 y 100", domain.ErrorInvalidInput)
+		}
+		promo.Amount = domain.Money{}
+	case domain.DiscountFixed:
+		if err := validatePrice(&promo.Amount); err != nil {
+			return fmt.Errorf("%w: %v", domain.ErrorInvalidInput, err)
+		}
+		promo.Percent = 0
+	default:
+		return fmt.Errorf("%w: el tipo de descuento debe ser percent o fixed", domain.ErrorInvalidInput)
+	}
+
+	if promo.ProductID != nil && promo.Category != "" {
+		return fmt.Errorf("%w: la promocion aplica a un producto o a una categoria, no a ambos", domain.ErrorInvalidInput)
+	}
+
+	if promo.ProductID != nil {
+		if _, err := s.appts.prodRepo.GetById(ctx, *promo.ProductID); err != nil {
+			if err == domain.ErrorNotFound {
+				return fmt.Errorf("%w: producto no encontrado", domain.ErrorInvalidInput)
+			}
+			return err
+		}
+	}
+
+	if promo.ValidFrom != nil && promo.ValidUntil != nil && !promo.ValidUntil.After(*promo.ValidFrom) {
+		return fmt.Errorf("%w: el fin de la vigencia debe ser posterior al inicio", domain.ErrorInvalidInput)
+	}
+
+	for _, day := range promo.Weekdays {
+		if day < time.Sunday || day > time.Saturday {
+			return fmt.Errorf("%w: dia de la semana invalido %d", domain.ErrorInvalidInput, day)
+		}
+	}
+	slices.Sort(promo.Weekdays)
+	promo.Weekdays = slices.Compact(promo.Weekdays)
+
+	if promo.MaxUses < 0 || promo.MaxUsesPerClient < 0 {
+		return fmt.Errorf("%w: los limites de uso no pueden ser negativos", domain.ErrorInvalidInput)
+	}
+
+	promo.Active = true
+	return s.repo.Create(ctx, promo)
+}
+
+func (s *PromotionService) List(ctx context.Context) ([]domain.Promotion, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *PromotionService) GetByCode(ctx context.Context, code string) (*domain.Promotion, error) {
+	return s.repo.GetByCode(ctx, normalizePromoCode(code))
+}
+
+// Deactivate impide nuevos usos de la promocion. Las citas que ya la tienen
+// conservan el descuento.
+func (s *PromotionService) Deactivate(ctx context.Context, code string) (*domain.Promotion, error) {
+	promo, err := s.GetByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	promo.Active = false
+	if err := s.repo.Update(ctx, promo); err != nil {
+		return nil, err
+	}
+
+	return promo, nil
+}
+
+// Schedule agenda la cita aplicando el codigo promocional. La promocion se
+// valida contra las lineas de la cita antes de reservar el turno, y la cita y
+// el uso se guardan en la misma transaccion: si otro canje simultaneo alcanzo
+// el limite, la cita no se crea.
+func (s *PromotionService) Schedule(ctx context.Context, appt *domain.Appointment, code string) (*domain.PromotionRedemption, error) {
+	promo, err := s.GetByCode(ctx, code)
+	if err != nil {
+		if err == domain.ErrorNotFound {
+			return nil, errors.New("codigo promocional no encontrado")
+		}
+		return nil, err
+	}
+
+	// Las lineas se cargan antes para saber si la promocion aplica a ellas
+	products, err := s.appts.loadItems(ctx, appt.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkPromotion(promo, appt); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkLimits(ctx, promo, appt.ClientID); err != nil {
+		return nil, err
+	}
+
+	var redemption *domain.PromotionRedemption
+	err = s.appts.tx.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.appts.schedule(ctx, appt, products); err != nil {
+			return err
+		}
+
+		redemption, err = s.redeem(ctx, promo, appt)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return redemption, nil
+}
+
+// Apply aplica el codigo promocional a una cita ya agendada
+func (s *PromotionService) Apply(ctx context.Context, apptID uint, code string) (*domain.PromotionRedemption, error) {
+	promo, err := s.GetByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	appt, err := s.appts.apptRepo.GetById(ctx, apptID)
+	if err != nil {
+		return nil, err
+	}
+
+	if appt.Status != domain.StatusScheduled && appt.Status != domain.StatusConfirmed {
+		return nil, fmt.Errorf("no se puede aplicar una promocion a una cita en estado %s", appt.Status)
+	}
+
+	return s.redeem(ctx, promo, appt)
+}
+
+// redeem verifica que la promocion aplique a la cita y registra su uso. Los
+// limites de uso se verifican al registrarlo.
+func (s *PromotionService) redeem(ctx context.Context, promo *domain.Promotion, appt *domain.Appointment) (*domain.PromotionRedemption, error) {
+	if err := checkPromotion(promo, appt); err != nil {
+		return nil, err
+	}
+
+	redemption := &domain.PromotionRedemption{
+		PromotionID:   promo.ID,
+		AppointmentID: appt.ID,
+		ClientID:      appt.ClientID,
+	}
+	if err := s.repo.Redeem(ctx, redemption); err != nil {
+		return nil, err
+	}
+
+	redemption.Promotion = promo
+	return redemption, nil
+}
+
+// checkLimits verifica que la promocion no haya alcanzado sus limites de uso.
+// El repositorio los vuelve a verificar al registrar el uso.
+func (s *PromotionService) checkLimits(ctx context.Context, promo *domain.Promotion, clientID uint) error {
+	if promo.MaxUses > 0 {
+		uses, err := s.repo.CountUses(ctx, promo.ID, 0)
+		if err != nil {
+			return err
+		}
+		if uses >= int64(promo.MaxUses) {
+			return domain.ErrorPromotionLimit
+		}
+	}
+
+	if promo.MaxUsesPerClient > 0 {
+		uses, err := s.repo.CountUses(ctx, promo.ID, clientID)
+		if err != nil {
+			return err
+		}
+		if uses >= int64(promo.MaxUsesPerClient) {
+			return domain.ErrorPromotionLimit
+		}
+	}
+
+	return nil
+}
+
+// StatusChanged libera la promocion de las citas canceladas para que no
+// cuente en los limites de uso
+func (s *PromotionService) StatusChanged(ctx context.Context, appt *domain.Appointment, from domain.AppointmentStatus) error {
+	if appt.Status != domain.StatusCancelled {
+		return nil
+	}
+
+	if err := s.repo.Release(ctx, appt.ID, time.Now()); err != nil {
+		return fmt.Errorf("liberando la promocion de la cita %d: %w", appt.ID, err)
+	}
+
+	return nil
+}
+
+// Adjustments informa el descuento de la promocion aplicada a la cita
+func (s *PromotionService) Adjustments(ctx context.Context, appt *domain.Appointment) ([]domain.TotalLine, error) {
+	redemption, err := s.repo.GetRedemption(ctx, appt.ID)
+	if err != nil {
+		if err == domain.ErrorNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	amount := promotionDiscount(redemption.Promotion, appt.Items)
+	if !amount.IsPositive() {
+		return nil, nil
+	}
+
+	description := "Promocion " + redemption.Promotion.Code
+	if redemption.Promotion.Description != "" {
+		description += ": " + redemption.Promotion.Description
+	}
+
+	// Los descuentos acotados se reparten solo entre las lineas alcanzadas
+	line := domain.TotalLine{
+		Kind:        "promotion",
+		Description: description,
+		Amount:      amount,
+	}
+	if redemption.Promotion.ProductID != nil || redemption.Promotion.Category != "" {
+		for _, item := range appt.Items {
+			if promotionCovers(redemption.Promotion, item) {
+				line.ItemIDs = append(line.ItemIDs, item.ID)
+			}
+		}
+	}
+
+	return []domain.TotalLine{line}, nil
+}
+
+// checkPromotion verifica que la promocion este activa, vigente el dia de la
+// cita y que descuente algo sobre sus lineas
+func checkPromotion(promo *domain.Promotion, appt *domain.Appointment) error {
+	if !promo.Active {
+		return errors.New("la promocion no esta activa")
+	}
+
+	if promo.ValidFrom != nil && appt.StartTime.Before(*promo.ValidFrom) {
+		return errors.New("la promocion aun no esta vigente para la fecha de la cita")
+	}
+
+	if promo.ValidUntil != nil && !appt.StartTime.Before(*promo.ValidUntil) {
+		return errors.New("la promocion ya no esta vigente para la fecha de la cita")
+	}
+
+	if len(promo.Weekdays) > 0 && !slices.Contains(promo.Weekdays, appt.StartTime.Weekday()) {
+		return errors.New("la promocion no aplica el dia de la semana de la cita")
+	}
+
+	if !promotionDiscount(promo, appt.Items).IsPositive() {
+		return errors.New("la promocion no aplica a los servicios de la cita")
+	}
+
+	return nil
+}
+
+// promotionDiscount calcula el descuento sobre las lineas alcanzadas por la
+// promocion. Un descuento fijo no supera lo que suman esas lineas.
+func promotionDiscount(promo *domain.Promotion, items []domain.AppointmentItem) domain.Money {
+	var base domain.Money
+	for _, item := range items {
+		if promotionCovers(promo, item) {
+			base = base.Add(item.Total())
+		}
+	}
+
+	if promo.Kind == domain.DiscountFixed {
+		if !promo.Amount.SameCurrency(base) {
+			return domain.Money{}
+		}
+		return promo.Amount.Min(base)
+	}
+
+	return base.Percent(promo.Percent)
+}
+
+// promotionCovers indica si la linea entra en el alcance de la promocion
+func promotionCovers(promo *domain.Promotion, item domain.AppointmentItem) bool {
+	switch {
+	case promo.ProductID != nil:
+		return item.ProductID != nil && *item.ProductID == *promo.ProductID
+	case promo.Category != "":
+		return strings.EqualFold(item.Category, promo.Category)
+	default:
+		return true
+	}
+}
+
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
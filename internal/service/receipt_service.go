@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"github.com/alexnt4/barber-api/internal/receipt"
+)
+
+// ReceiptConfig son los datos de la barberia y el prefijo de los recibos
+type ReceiptConfig struct {
+	Shop   receipt.Shop
+	Prefix string
+}
+
+type ReceiptService struct {
+	repo  domain.ReceiptRepo
+	appts *AppointmentService
+	cfg   ReceiptConfig
+}
+
+// NewReceiptService crea el servicio y lo registra en las citas para emitir
+// el recibo al completarlas
+func NewReceiptService(r domain.ReceiptRepo, a *AppointmentService, cfg ReceiptConfig) *ReceiptService {
+	s := &ReceiptService{r, a, cfg}
+	a.AddStatusListener(s)
+	return s
+}
+
+// Get devuelve el recibo de una cita completada. Las lineas, descuentos e
+// impuestos son los del momento de emitirlo; los pagos y el saldo se leen al
+// servirlo porque la cita puede pagarse despues de completada. Si la cita
+// todavia no tiene recibo se emite en ese momento.
+func (s *ReceiptService) Get(ctx context.Context, apptID uint) (*receipt.Document, error) {
+	issued, err := s.repo.GetByAppointment(ctx, apptID)
+	if err == domain.ErrorNotFound {
+		issued, err = s.issue(ctx, apptID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	appt, err := s.appts.apptRepo.GetById(ctx, apptID)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.appts.appointmentPayments(ctx, appt)
+	if err != nil {
+		return nil, err
+	}
+
+	total := *issued.Total
+	applyPayments(&total, payments)
+
+	return &receipt.Document{
+		Shop:             s.cfg.Shop,
+		Number:           receipt.FormatNumber(s.cfg.Prefix, issued.Number),
+		IssuedAt:         issued.IssuedAt,
+		ClientName:       issued.ClientName,
+		BarberName:       issued.BarberName,
+		AppointmentStart: issued.AppointmentStart,
+		Total:            &total,
+	}, nil
+}
+
+// StatusChanged emite el recibo al completar la cita
+func (s *ReceiptService) StatusChanged(ctx context.Context, appt *domain.Appointment, from domain.AppointmentStatus) error {
+	if appt.Status != domain.StatusCompleted {
+		return nil
+	}
+
+	if _, err := s.issue(ctx, appt.ID); err != nil {
+		return fmt.Errorf("emitiendo el recibo de la cita %d: %w", appt.ID, err)
+	}
+
+	return nil
+}
+
+// issue numera el recibo de la cita guardando el total y los nombres que se
+// imprimen
+func (s *ReceiptService) issue(ctx context.Context, apptID uint) (*domain.Receipt, error) {
+	appt, err := s.appts.apptRepo.GetById(ctx, apptID)
+	if err != nil {
+		return nil, err
+	}
+
+	if appt.Status != domain.StatusCompleted {
+		return nil, domain.ErrorNotCompleted
+	}
+
+	total, err := s.appts.GetTotalPrice(ctx, appt.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	issued := &domain.Receipt{
+		AppointmentID:    appt.ID,
+		IssuedAt:         time.Now(),
+		AppointmentStart: appt.StartTime,
+		Total:            total,
+	}
+	if appt.Client != nil {
+		issued.ClientName = appt.Client.Name
+	}
+	if appt.Barber != nil {
+		issued.BarberName = appt.Barber.Name
+	}
+
+	return s.repo.Issue(ctx, issued)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxOccurrences limita la cantidad de citas que genera una regla
+const maxOccurrences = 52
+
+// recurrenceRule es el subconjunto de RRULE (RFC 5545) que soporta la API:
+// FREQ=WEEKLY|MONTHLY, INTERVAL y COUNT o UNTIL
+type recurrenceRule struct {
+	freq     string
+	interval int
+	count    int
+	until    time.Time
+}
+
+// parseRecurrenceRule interpreta la regla. Un UNTIL con solo la fecha se toma
+// en la zona horaria indicada, la de la barberia.
+func parseRecurrenceRule(rule string, loc *time.Location) (*recurrenceRule, error) {
+	r := &recurrenceRule{interval: 1}
+
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	for _, part := range strings.Split(rule, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("regla de recurrencia invalida: %q", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.freq = strings.ToUpper(value)
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return nil, errors.New("INTERVAL debe ser un entero positivo")
+			}
+			r.interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return nil, errors.New("COUNT debe ser un entero positivo")
+			}
+			r.count = count
+		case "UNTIL":
+			until, err := parseUntil(value, loc)
+			if err != nil {
+				return nil, err
+			}
+			r.until = until
+		default:
+			return nil, fmt.Errorf("parametro de recurrencia no soportado: %s", key)
+		}
+	}
+
+	if r.freq != "WEEKLY" && r.freq != "MONTHLY" {
+		return nil, errors.New("FREQ debe ser WEEKLY o MONTHLY")
+	}
+
+	if r.count == 0 && r.until.IsZero() {
+		return nil, errors.New("la recurrencia debe indicar COUNT o UNTIL")
+	}
+
+	if r.count > 0 && !r.until.IsZero() {
+		return nil, errors.New("COUNT y UNTIL no pueden usarse juntos")
+	}
+
+	if r.count > maxOccurrences {
+		return nil, fmt.Errorf("la recurrencia no puede generar mas de %d citas", maxOccurrences)
+	}
+
+	return r, nil
+}
+
+// parseUntil acepta UNTIL como fecha (20261231) en la zona indicada o fecha y
+// hora UTC (20261231T235959Z)
+func parseUntil(value string, loc *time.Location) (time.Time, error) {
+	if until, err := time.Parse("20060102T150405Z", value); err == nil {
+		return until, nil
+	}
+
+	until, err := time.ParseInLocation("20060102", value, loc)
+	if err != nil {
+		return time.Time{}, errors.New("UNTIL invalido, use AAAAMMDD o AAAAMMDDTHHMMSSZ")
+	}
+
+	// UNTIL como fecha incluye todo ese dia
+	return until.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+}
+
+// occurrences devuelve las horas de inicio de cada cita de la serie, incluida la primera
+func (r *recurrenceRule) occurrences(start time.Time) []time.Time {
+	var starts []time.Time
+
+	for i := 0; len(starts) < maxOccurrences; i++ {
+		var next time.Time
+		if r.freq == "WEEKLY" {
+			next = start.AddDate(0, 0, 7*r.interval*i)
+		} else {
+			next = start.AddDate(0, r.interval*i, 0)
+			// Los meses sin ese dia se omiten, como indica RFC 5545
+			if next.Day() != start.Day() {
+				continue
+			}
+		}
+
+		if !r.until.IsZero() && next.After(r.until) {
+			break
+		}
+
+		starts = append(starts, next)
+
+		if r.count > 0 && len(starts) == r.count {
+			break
+		}
+	}
+
+	return starts
+}
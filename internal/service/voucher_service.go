@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+)
+
+// voucherAlphabet evita caracteres que se confunden al dictar el codigo
+const voucherAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+type VoucherService struct {
+	repo  domain.VoucherRepo
+	appts *AppointmentService
+}
+
+// NewVoucherService crea el servicio y lo registra en las citas para informar
+// los canjes como pagos y devolver el saldo de las citas canceladas
+func NewVoucherService(v domain.VoucherRepo, a *AppointmentService) *VoucherService {
+	s := &VoucherService{v, a}
+	a.AddStatusListener(s)
+	a.AddPaymentSource(s)
+	return s
+}
+
+// Issue emite una tarjeta con su saldo inicial. Si no trae codigo se genera
+// uno aleatorio.
+func (s *VoucherService) Issue(ctx context.Context, voucher *domain.Voucher) error {
+	if !voucher.InitialBalance.IsPositive() {
+		return fmt.Errorf("%w: el saldo inicial debe ser mayor a cero", domain.ErrorInvalidInput)
+	}
+
+	voucher.InitialBalance.Currency = strings.ToUpper(strings.TrimSpace(voucher.InitialBalance.Currency))
+	if voucher.InitialBalance.Currency == "" {
+		voucher.InitialBalance.Currency = domain.DefaultCurrency
+	}
+
+	if !domain.ValidCurrency(voucher.InitialBalance.Currency) {
+		return fmt.Errorf("%w: moneda invalida %q", domain.ErrorInvalidInput, voucher.InitialBalance.Currency)
+	}
+
+	if voucher.ExpiresAt != nil && voucher.ExpiresAt.Before(time.Now()) {
+		return fmt.Errorf("%w: la fecha de vencimiento debe ser futura", domain.ErrorInvalidInput)
+	}
+
+	voucher.Balance = voucher.InitialBalance
+
+	if voucher.Code != "" {
+		voucher.Code = normalizeVoucherCode(voucher.Code)
+		return s.repo.Create(ctx, voucher)
+	}
+
+	// Reintentar si el codigo generado ya existe
+	for range 3 {
+		code, err := generateVoucherCode()
+		if err != nil {
+			return err
+		}
+
+		voucher.Code = code
+		err = s.repo.Create(ctx, voucher)
+		if !errors.Is(err, domain.ErrorDuplicateCode) {
+			return err
+		}
+	}
+
+	return domain.ErrorDuplicateCode
+}
+
+// GetByCode devuelve la tarjeta con su saldo disponible
+func (s *VoucherService) GetByCode(ctx context.Context, code string) (*domain.Voucher, error) {
+	return s.repo.GetByCode(ctx, normalizeVoucherCode(code))
+}
+
+// Redeem canjea saldo de la tarjeta contra el pendiente de la cita. Con
+// cents en cero se usa todo el saldo que cubra el pendiente.
+func (s *VoucherService) Redeem(ctx context.Context, code string, apptID uint, cents int64) (*domain.VoucherTransaction, error) {
+	if cents < 0 {
+		return nil, fmt.Errorf("%w: el monto no puede ser negativo", domain.ErrorInvalidInput)
+	}
+
+	voucher, err := s.GetByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if voucher.ExpiresAt != nil && !voucher.ExpiresAt.After(now) {
+		return nil, domain.ErrorVoucherExpired
+	}
+
+	appt, err := s.appts.apptRepo.GetById(ctx, apptID)
+	if err != nil {
+		return nil, err
+	}
+
+	if appt.Status == domain.StatusCancelled || appt.Status == domain.StatusNoShow {
+		return nil, fmt.Errorf("no se puede pagar una cita en estado %s", appt.Status)
+	}
+
+	total, err := s.appts.GetTotalPrice(ctx, apptID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !voucher.Balance.SameCurrency(total.Due) {
+		return nil, fmt.Errorf("%w: la tarjeta es en %s y la cita en %s", domain.ErrorCurrencyMismatch, voucher.Balance.Currency, total.Due.Currency)
+	}
+
+	amount := domain.NewMoney(cents, voucher.Balance.Currency)
+	if cents == 0 {
+		amount = voucher.Balance.Min(total.Due)
+	}
+
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("%w: la cita no tiene saldo pendiente", domain.ErrorInvalidInput)
+	}
+
+	if amount.Cents > total.Due.Cents {
+		return nil, fmt.Errorf("%w: el monto supera el pendiente de la cita", domain.ErrorInvalidInput)
+	}
+
+	vtx := &domain.VoucherTransaction{
+		VoucherID:     voucher.ID,
+		AppointmentID: appt.ID,
+		Kind:          domain.VoucherRedeem,
+		Amount:        amount,
+	}
+
+	// El saldo de la tarjeta y el pendiente de la cita se vuelven a verificar
+	// al descontarlo por si hubo otro pago
+	if err := s.repo.Redeem(ctx, vtx, now, s.appts.dueCheck(apptID, amount)); err != nil {
+		return nil, err
+	}
+
+	return vtx, nil
+}
+
+// StatusChanged devuelve a las tarjetas el saldo canjeado en citas que se
+// cancelan o a las que el cliente no asiste
+func (s *VoucherService) StatusChanged(ctx context.Context, appt *domain.Appointment, from domain.AppointmentStatus) error {
+	if appt.Status != domain.StatusCancelled && appt.Status != domain.StatusNoShow {
+		return nil
+	}
+
+	txs, err := s.repo.ListTransactions(ctx, appt.ID)
+	if err != nil {
+		return fmt.Errorf("consultando las tarjetas de regalo de la cita %d: %w", appt.ID, err)
+	}
+
+	for _, r := range redeemedByVoucher(txs) {
+		if !r.amount.IsPositive() {
+			continue
+		}
+
+		err := s.repo.Refund(ctx, &domain.VoucherTransaction{
+			VoucherID:     r.voucherID,
+			AppointmentID: appt.ID,
+			Kind:          domain.VoucherRefund,
+			Amount:        r.amount,
+		})
+		if err != nil {
+			return fmt.Errorf("devolviendo saldo a la tarjeta %d: %w", r.voucherID, err)
+		}
+	}
+
+	return nil
+}
+
+// Payments informa lo pagado con cada tarjeta en la cita
+func (s *VoucherService) Payments(ctx context.Context, appt *domain.Appointment) ([]domain.TotalLine, error) {
+	txs, err := s.repo.ListTransactions(ctx, appt.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := map[uint]string{}
+	for _, vtx := range txs {
+		if vtx.Voucher != nil {
+			codes[vtx.VoucherID] = vtx.Voucher.Code
+		}
+	}
+
+	var lines []domain.TotalLine
+	for _, r := range redeemedByVoucher(txs) {
+		if r.amount.IsPositive() {
+			lines = append(lines, domain.TotalLine{
+				Kind:        "voucher",
+				Description: "Tarjeta de regalo " + codes[r.voucherID],
+				Amount:      r.amount,
+			})
+		}
+	}
+
+	return lines, nil
+}
+
+type voucherRedeemed struct {
+	voucherID uint
+	amount    domain.Money
+}
+
+// redeemedByVoucher devuelve por tarjeta, en orden de uso, el saldo canjeado
+// que no fue devuelto
+func redeemedByVoucher(txs []domain.VoucherTransaction) []voucherRedeemed {
+	var result []voucherRedeemed
+	index := map[uint]int{}
+	for _, vtx := range txs {
+		i, ok := index[vtx.VoucherID]
+		if !ok {
+			result = append(result, voucherRedeemed{voucherID: vtx.VoucherID})
+			i = len(result) - 1
+			index[vtx.VoucherID] = i
+		}
+
+		if vtx.Kind == domain.VoucherRefund {
+			result[i].amount = result[i].amount.Sub(vtx.Amount)
+		} else {
+			result[i].amount = result[i].amount.Add(vtx.Amount)
+		}
+	}
+
+	return result
+}
+
+func generateVoucherCode() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	var code strings.Builder
+	for i, b := range buf {
+		if i > 0 && i%4 == 0 {
+			code.WriteByte('-')
+		}
+		code.WriteByte(voucherAlphabet[int(b)%len(voucherAlphabet)])
+	}
+
+	return code.String(), nil
+}
+
+func normalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+)
+
+type holdKey struct{}
+
+type WaitlistService struct {
+	waitRepo     domain.WaitlistRepo
+	appts        *AppointmentService
+	holdDuration time.Duration
+}
+
+// NewWaitlistService crea el servicio y lo registra en las citas para ofrecer
+// los horarios que se liberan y respetar las reservas temporales
+func NewWaitlistService(w domain.WaitlistRepo, a *AppointmentService, holdDuration time.Duration) *WaitlistService {
+	s := &WaitlistService{w, a, holdDuration}
+	a.SetSlotHolder(s)
+	a.AddStatusListener(s)
+	return s
+}
+
+func (s *WaitlistService) Join(ctx context.Context, entry *domain.WaitlistEntry) error {
+	// Validaciones basicas
+	if err := s.appts.validateClient(ctx, entry.ClientID); err != nil {
+		return err
+	}
+
+	if !entry.WindowEnd.After(entry.WindowStart) {
+		return errors.New("el fin de la ventana debe ser posterior al inicio")
+	}
+
+	if entry.WindowEnd.Before(time.Now()) {
+		return errors.New("la ventana deseada ya paso")
+	}
+
+	if entry.BarberID != nil {
+		if err := s.appts.validateBarber(ctx, *entry.BarberID); err != nil {
+			return err
+		}
+	}
+
+	// Validar productos y que el servicio entre en la ventana
+	if err := s.appts.loadProducts(ctx, entry.Products); err != nil {
+		return err
+	}
+
+	required := requiredDuration(entry.Products)
+	if required <= 0 {
+		return errors.New("seleccione al menos un servicio con duracion")
+	}
+
+	if entry.WindowEnd.Sub(entry.WindowStart) < required {
+		return errors.New("la ventana deseada es mas corta que la duracion de los servicios")
+	}
+
+	entry.Status = domain.WaitlistWaiting
+	return s.waitRepo.Create(ctx, entry)
+}
+
+func (s *WaitlistService) GetByID(ctx context.Context, id uint) (*domain.WaitlistEntry, error) {
+	return s.waitRepo.GetById(ctx, id)
+}
+
+func (s *WaitlistService) List(ctx context.Context, status domain.WaitlistStatus) ([]domain.WaitlistEntry, error) {
+	if err := s.ExpireHolds(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.waitRepo.List(ctx, status)
+}
+
+// Leave saca al cliente de la lista de espera, liberando la oferta si tenia una
+func (s *WaitlistService) Leave(ctx context.Context, id uint) error {
+	entry, err := s.waitRepo.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if entry.Status != domain.WaitlistWaiting && entry.Status != domain.WaitlistOffered {
+		return errors.New("la solicitud ya no esta en lista de espera")
+	}
+
+	wasOffered := entry.Status == domain.WaitlistOffered
+	barberID, start, end := offeredSlot(entry)
+
+	clearOffer(entry)
+	entry.Status = domain.WaitlistCancelled
+	if err := s.waitRepo.UpdateFrom(ctx, entry, domain.WaitlistWaiting, domain.WaitlistOffered); err != nil {
+		return err
+	}
+
+	if wasOffered {
+		return s.offerSlot(ctx, barberID, start, end, entry.ID)
+	}
+
+	return nil
+}
+
+// Accept convierte la oferta vigente en una cita
+func (s *WaitlistService) Accept(ctx context.Context, id uint) (*domain.Appointment, error) {
+	entry, err := s.waitRepo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Status != domain.WaitlistOffered {
+		return nil, errors.New("la solicitud no tiene una oferta pendiente")
+	}
+
+	if !entry.HoldExpiresAt.After(time.Now()) {
+		if err := s.ExpireHolds(ctx); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("la oferta expiro")
+	}
+
+	items := make([]domain.AppointmentItem, len(entry.Products))
+	for i, prod := range entry.Products {
+		items[i] = domain.AppointmentItem{ProductID: &prod.ID}
+	}
+
+	appt := &domain.Appointment{
+		ClientID:  entry.ClientID,
+		BarberID:  *entry.OfferBarberID,
+		StartTime: *entry.OfferStart,
+		EndTime:   *entry.OfferEnd,
+		Items:     items,
+	}
+
+	// La oferta se toma antes de reservar y todo ocurre en una transaccion:
+	// si vence o se rechaza a la vez, la cita no se crea
+	err = s.appts.tx.InTransaction(ctx, func(ctx context.Context) error {
+		entry.Status = domain.WaitlistAccepted
+		entry.HoldExpiresAt = nil
+		if err := s.waitRepo.UpdateFrom(ctx, entry, domain.WaitlistOffered); err != nil {
+			return err
+		}
+
+		// La reserva propia no debe bloquear la cita que la convierte
+		if err := s.appts.Schedule(context.WithValue(ctx, holdKey{}, entry.ID), appt); err != nil {
+			return err
+		}
+
+		entry.AppointmentID = &appt.ID
+		return s.waitRepo.Update(ctx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return appt, nil
+}
+
+// Decline rechaza la oferta: el cliente sigue en espera y el horario se
+// ofrece al siguiente
+func (s *WaitlistService) Decline(ctx context.Context, id uint) error {
+	entry, err := s.waitRepo.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if entry.Status != domain.WaitlistOffered {
+		return errors.New("la solicitud no tiene una oferta pendiente")
+	}
+
+	barberID, start, end := offeredSlot(entry)
+
+	clearOffer(entry)
+	entry.Status = domain.WaitlistWaiting
+	if err := s.waitRepo.UpdateFrom(ctx, entry, domain.WaitlistOffered); err != nil {
+		return err
+	}
+
+	return s.offerSlot(ctx, barberID, start, end, entry.ID)
+}
+
+// ExpireHolds devuelve a la espera las ofertas vencidas, ofrece esos horarios
+// al siguiente cliente y vence las solicitudes cuya ventana ya paso. Cada
+// oferta vencida se reclama de forma atomica, asi que puede correr a la vez
+// desde el temporizador y desde las consultas sin procesarla dos veces.
+func (s *WaitlistService) ExpireHolds(ctx context.Context) error {
+	now := time.Now()
+
+	expired, err := s.waitRepo.ClaimExpiredHolds(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for i := range expired {
+		entry := &expired[i]
+		barberID, start, end := offeredSlot(entry)
+
+		if err := s.offerSlot(ctx, barberID, start, end, entry.ID); err != nil {
+			return err
+		}
+	}
+
+	return s.waitRepo.ExpirePastWindows(ctx, now)
+}
+
+// StatusChanged ofrece a la lista de espera el horario de cada cita cancelada
+func (s *WaitlistService) StatusChanged(ctx context.Context, appt *domain.Appointment, from domain.AppointmentStatus) error {
+	if appt.Status != domain.StatusCancelled {
+		return nil
+	}
+
+	if err := s.offerSlot(ctx, appt.BarberID, appt.StartTime, appt.EndTime, 0); err != nil {
+		return fmt.Errorf("ofreciendo el horario liberado a la lista de espera: %w", err)
+	}
+
+	return nil
+}
+
+// HeldSlots devuelve las ofertas vigentes del barbero en el rango, salvo la
+// que se esta aceptando en este contexto
+func (s *WaitlistService) HeldSlots(ctx context.Context, barberID uint, start, end time.Time) ([]domain.AvailableSlot, error) {
+	holds, err := s.waitRepo.FindActiveHolds(ctx, barberID, start, end, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	accepting, _ := ctx.Value(holdKey{}).(uint)
+
+	slots := []domain.AvailableSlot{}
+	for _, hold := range holds {
+		if hold.ID == accepting {
+			continue
+		}
+		slots = append(slots, domain.AvailableSlot{
+			BarberID:  *hold.OfferBarberID,
+			StartTime: *hold.OfferStart,
+			EndTime:   *hold.OfferEnd,
+		})
+	}
+
+	return slots, nil
+}
+
+// offerSlot ofrece el horario liberado al primer cliente en espera cuyo
+// servicio entre en el hueco y en su ventana deseada
+func (s *WaitlistService) offerSlot(ctx context.Context, barberID uint, start, end time.Time, skipID uint) error {
+	now := time.Now()
+	if start.Before(now) {
+		start = now
+	}
+	if !end.After(start) {
+		return nil
+	}
+
+	// Las ofertas de un barbero se hacen de a una, asi dos horarios liberados
+	// a la vez no ofrecen el mismo hueco a dos clientes
+	return s.appts.tx.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.waitRepo.LockBarber(ctx, barberID); err != nil {
+			return err
+		}
+
+		candidates, err := s.waitRepo.FindCandidates(ctx, barberID, start, end)
+		if err != nil {
+			return err
+		}
+
+		for i := range candidates {
+			entry := &candidates[i]
+			if entry.ID == skipID {
+				continue
+			}
+
+			offerStart := start
+			if entry.WindowStart.After(offerStart) {
+				offerStart = entry.WindowStart
+			}
+			offerEnd := offerStart.Add(requiredDuration(entry.Products))
+
+			if offerEnd.After(end) || offerEnd.After(entry.WindowEnd) {
+				continue
+			}
+
+			// El horario pudo haberse ocupado desde que se libero
+			overlapping, err := s.appts.apptRepo.FindOverlapping(ctx, barberID, offerStart, offerEnd, 0)
+			if err != nil {
+				return err
+			}
+			held, err := s.HeldSlots(ctx, barberID, offerStart, offerEnd)
+			if err != nil {
+				return err
+			}
+			if len(overlapping) > 0 || len(held) > 0 {
+				continue
+			}
+
+			holdExpiresAt := now.Add(s.holdDuration)
+			entry.Status = domain.WaitlistOffered
+			entry.OfferBarberID = &barberID
+			entry.OfferStart = &offerStart
+			entry.OfferEnd = &offerEnd
+			entry.HoldExpiresAt = &holdExpiresAt
+
+			// Otra liberacion pudo haberle ofrecido un horario de otro barbero
+			err = s.waitRepo.UpdateFrom(ctx, entry, domain.WaitlistWaiting)
+			if err == domain.ErrorWaitlistChanged {
+				continue
+			}
+			return err
+		}
+
+		return nil
+	})
+}
+
+func offeredSlot(entry *domain.WaitlistEntry) (uint, time.Time, time.Time) {
+	if entry.OfferBarberID == nil || entry.OfferStart == nil || entry.OfferEnd == nil {
+		return 0, time.Time{}, time.Time{}
+	}
+
+	return *entry.OfferBarberID, *entry.OfferStart, *entry.OfferEnd
+}
+
+func clearOffer(entry *domain.WaitlistEntry) {
+	entry.OfferBarberID = nil
+	entry.OfferStart = nil
+	entry.OfferEnd = nil
+	entry.HoldExpiresAt = nil
+}
@@ -1,8 +1,11 @@
 package http
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/alexnt4/barber-api/internal/domain"
@@ -11,25 +14,94 @@ import (
 )
 
 type AppointmentHandler struct {
-	svc *service.AppointmentService
+	svc    *service.AppointmentService
+	promos *service.PromotionService
 }
 
-func NewAppoinmentHandler(svc *service.AppointmentService) *AppointmentHandler {
-	return &AppointmentHandler{svc}
+func NewAppoinmentHandler(svc *service.AppointmentService, promos *service.PromotionService) *AppointmentHandler {
+	return &AppointmentHandler{svc, promos}
 }
 
 type CreateAppointmentRequest struct {
-	ClientName string `json:"client_name" binding:"required"`
-	StartTime  string `json:"start_time" binding:"required"`
-	EndTime    string `json:"end_time" binding:"required"`
-	Products   []uint `json:"products"`
+	ClientID   uint                     `json:"client_id" binding:"required"`
+	BarberID   uint                     `json:"barber_id" binding:"required"`
+	StartTime  string                   `json:"start_time" binding:"required"`
+	EndTime    string                   `json:"end_time"`
+	Products   []uint                   `json:"products"`
+	Items      []AppointmentItemRequest `json:"items" binding:"dive"`
+	Recurrence string                   `json:"recurrence"`
+	PromoCode  string                   `json:"promo_code"`
+	// OverrideNoShowBlock permite al personal agendar a un cliente bloqueado
+	// por inasistencias
+	OverrideNoShowBlock bool `json:"override_no_show_block"`
 }
 
 type UpdateAppointmentRequest struct {
-	ClientName string `json:"client_name" binding:"required"`
-	StartTime  string `json:"start_time" binding:"required"`
-	EndTime    string `json:"end_time" binding:"required"`
-	Products   []uint `json:"products"`
+	ClientID  uint                     `json:"client_id" binding:"required"`
+	BarberID  uint                     `json:"barber_id" binding:"required"`
+	StartTime string                   `json:"start_time" binding:"required"`
+	EndTime   string                   `json:"end_time"`
+	Products  []uint                   `json:"products"`
+	Items     []AppointmentItemRequest `json:"items" binding:"dive"`
+}
+
+// AppointmentItemRequest es una linea de la cita: un producto del catalogo,
+// opcionalmente con otro precio y su motivo, o una linea libre con
+// descripcion, precio e impuesto
+type AppointmentItemRequest struct {
+	ProductID   *uint  `json:"product_id"`
+	Name        string `json:"name"`
+	Quantity    int    `json:"quantity" binding:"gte=0"`
+	PriceCents  *int64 `json:"price_cents" binding:"omitempty,gte=0"`
+	Currency    string `json:"currency"`
+	Reason      string `json:"reason"`
+	TaxRateBps  *int   `json:"tax_rate_bps"`
+	TaxIncluded *bool  `json:"tax_included"`
+	TaxExempt   bool   `json:"tax_exempt"`
+}
+
+// appointmentItems arma las lineas de la cita. products es la lista simple de
+// IDs de producto, donde un producto repetido suma cantidad en la misma linea.
+func appointmentItems(products []uint, reqItems []AppointmentItemRequest) ([]domain.AppointmentItem, error) {
+	items := []domain.AppointmentItem{}
+	index := map[uint]int{}
+	for _, id := range products {
+		if i, ok := index[id]; ok {
+			items[i].Quantity++
+			continue
+		}
+		index[id] = len(items)
+		items = append(items, domain.AppointmentItem{ProductID: &id, Quantity: 1})
+	}
+
+	for _, req := range reqItems {
+		item := domain.AppointmentItem{
+			ProductID:      req.ProductID,
+			Quantity:       req.Quantity,
+			OverrideReason: req.Reason,
+		}
+
+		if req.PriceCents != nil {
+			currency := strings.ToUpper(strings.TrimSpace(req.Currency))
+			if currency == "" {
+				currency = domain.DefaultCurrency
+			}
+			item.UnitPrice = domain.NewMoney(*req.PriceCents, currency)
+		}
+
+		// El nombre de las lineas de producto sale del catalogo
+		if req.ProductID == nil {
+			if req.PriceCents == nil {
+				return nil, errors.New("las lineas sin producto requieren name y price_cents")
+			}
+			item.Name = req.Name
+			item.Tax = requestTax(req.TaxRateBps, req.TaxIncluded, req.TaxExempt)
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
 }
 
 func (h *AppointmentHandler) Create(c *gin.Context) {
@@ -46,26 +118,70 @@ func (h *AppointmentHandler) Create(c *gin.Context) {
 		return
 	}
 
-	endTime, err := time.Parse(time.RFC3339, req.EndTime)
+	// La hora de fin es opcional, se calcula con la duracion de los servicios
+	var endTime time.Time
+	if req.EndTime != "" {
+		endTime, err = time.Parse(time.RFC3339, req.EndTime)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "formato de fecha invalido para end_time, use RFC3339"})
+			return
+		}
+	}
+
+	items, err := appointmentItems(req.Products, req.Items)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "formato de fecha invalido para end_time, use RFC3339"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Mappear IDs a domain.Product
-	products := make([]domain.Product, len(req.Products))
-	for i, id := range req.Products {
-		products[i] = domain.Product{ID: id}
+	appt := &domain.Appointment{
+		ClientID:  req.ClientID,
+		BarberID:  req.BarberID,
+		StartTime: startTime,
+		EndTime:   endTime,
+		Items:     items,
 	}
 
-	appt := &domain.Appointment{
-		ClientName: req.ClientName,
-		StartTime:  startTime,
-		EndTime:    endTime,
-		Products:   products,
+	ctx := c.Request.Context()
+	if req.OverrideNoShowBlock {
+		ctx = service.WithNoShowOverride(ctx)
+	}
+
+	// Citas recurrentes, ej: FREQ=WEEKLY;INTERVAL=2;COUNT=6
+	if req.Recurrence != "" {
+		if req.PromoCode != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "los codigos promocionales no aplican a citas recurrentes"})
+			return
+		}
+
+		series, appts, conflicts, err := h.svc.ScheduleRecurring(ctx, appt, req.Recurrence)
+		if err != nil {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":     err.Error(),
+				"conflicts": conflicts,
+			})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"series":       series,
+			"appointments": appts,
+			"conflicts":    conflicts,
+		})
+		return
 	}
 
-	if err := h.svc.Schedule(c.Request.Context(), appt); err != nil {
+	if req.PromoCode != "" {
+		if _, err := h.promos.Schedule(ctx, appt, req.PromoCode); err != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, appt)
+		return
+	}
+
+	if err := h.svc.Schedule(ctx, appt); err != nil {
 		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		return
 	}
@@ -74,18 +190,77 @@ func (h *AppointmentHandler) Create(c *gin.Context) {
 }
 
 func (h *AppointmentHandler) List(c *gin.Context) {
-	appoinmets, err := h.svc.ListAll(c.Request.Context())
+	opts, err := parseAppointmentListOptions(c)
 	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	appoinmets, nextCursor, err := h.svc.List(c.Request.Context(), opts)
+	if err != nil {
+		if errors.Is(err, domain.ErrorInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"appoinmets": appoinmets,
-		"total":      len(appoinmets),
+		"appoinmets":  appoinmets,
+		"total":       len(appoinmets),
+		"next_cursor": nextCursor,
 	})
 }
 
+// parseAppointmentListOptions arma los filtros del listado a partir de los
+// parametros from, to, barber, client, status, product, sort, cursor y limit
+func parseAppointmentListOptions(c *gin.Context) (domain.AppointmentListOptions, error) {
+	opts := domain.AppointmentListOptions{
+		Status: domain.AppointmentStatus(c.Query("status")),
+		Cursor: c.Query("cursor"),
+	}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := parseQueryTime(fromStr)
+		if err != nil {
+			return opts, errors.New("formato de fecha invalido para from, use YYYY-MM-DD o RFC3339")
+		}
+		opts.From = &from
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := parseQueryTime(toStr)
+		if err != nil {
+			return opts, errors.New("formato de fecha invalido para to, use YYYY-MM-DD o RFC3339")
+		}
+		opts.To = &to
+	}
+
+	var err error
+	if opts.BarberID, err = parseQueryID(c, "barber"); err != nil {
+		return opts, err
+	}
+	if opts.ClientID, err = parseQueryID(c, "client"); err != nil {
+		return opts, err
+	}
+	if opts.ProductID, err = parseQueryID(c, "product"); err != nil {
+		return opts, err
+	}
+
+	column, desc := parseSort(c.DefaultQuery("sort", "start_time"))
+	if column != "start_time" {
+		return opts, errors.New("sort solo admite start_time o -start_time")
+	}
+	opts.SortDesc = desc
+
+	if opts.Limit, err = parseLimit(c); err != nil {
+		return opts, err
+	}
+
+	return opts, nil
+}
+
 func (h *AppointmentHandler) Get(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
@@ -125,26 +300,51 @@ func (h *AppointmentHandler) Update(c *gin.Context) {
 	startTime, err := time.Parse(time.RFC3339, req.StartTime)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "formato de fecha invalido para start_time, use RFC3339"})
+		return
+	}
+
+	// La hora de fin es opcional, se calcula con la duracion de los servicios
+	var endTime time.Time
+	if req.EndTime != "" {
+		endTime, err = time.Parse(time.RFC3339, req.EndTime)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "formato de fecha invalido para end_time, use RFC3339"})
+			return
+		}
 	}
 
-	endTime, err := time.Parse(time.RFC3339, req.EndTime)
+	items, err := appointmentItems(req.Products, req.Items)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "formato de fecha invalido para end_time, use RFC3339"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Mapear IDs a domain.Product
-	products := make([]domain.Product, len(req.Products))
-	for i, id := range req.Products {
-		products[i] = domain.Product{ID: id}
+	appt := &domain.Appointment{
+		ID:        uint(id),
+		ClientID:  req.ClientID,
+		BarberID:  req.BarberID,
+		StartTime: startTime,
+		EndTime:   endTime,
+		Items:     items,
 	}
 
-	appt := &domain.Appointment{
-		ID:         uint(id),
-		ClientName: req.ClientName,
-		StartTime:  startTime,
-		EndTime:    endTime,
-		Products:   products,
+	// scope=following modifica tambien las siguientes citas de la serie
+	if c.Query("scope") == "following" {
+		appts, conflicts, err := h.svc.UpdateFollowing(c.Request.Context(), uint(id), appt)
+		if err != nil {
+			if err == domain.ErrorNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "cita no encontrada"})
+				return
+			}
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"appointments": appts,
+			"conflicts":    conflicts,
+		})
+		return
 	}
 
 	if err := h.svc.Update(c.Request.Context(), uint(id), appt); err != nil {
@@ -155,7 +355,98 @@ func (h *AppointmentHandler) Update(c *gin.Context) {
 	c.JSON(http.StatusOK, appt)
 }
 
-func (h *AppointmentHandler) Delete(c *gin.Context) {
+func (h *AppointmentHandler) Cancel(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	// scope=following cancela tambien las siguientes citas de la serie
+	if c.Query("scope") == "following" {
+		appts, fees, conflicts, err := h.svc.CancelFollowing(c.Request.Context(), uint(id))
+		if err != nil {
+			if err == domain.ErrorNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "cita no encontrada"})
+				return
+			}
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":      "citas canceladas exitosamente",
+			"appointments": appts,
+			"fees":         fees,
+			"conflicts":    conflicts,
+		})
+		return
+	}
+
+	appt, fee, err := h.svc.Cancel(c.Request.Context(), uint(id))
+	if err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "cita no encontrada"})
+			return
+		}
+		if errors.Is(err, domain.ErrorInvalidTransition) || errors.Is(err, domain.ErrorCancelCutoff) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, cancelResponse(appt, fee))
+}
+
+// cancelResponse arma la respuesta de una cancelacion, con el cargo y un
+// aviso cuando se cancelo fuera del plazo gratuito
+func cancelResponse(appt any, fee *domain.Fee) gin.H {
+	resp := gin.H{
+		"message":     "cita cancelada exitosamente",
+		"appointment": appt,
+	}
+
+	if fee != nil {
+		resp["fee"] = fee
+		resp["warning"] = fmt.Sprintf("cancelacion fuera de plazo: se genero un cargo de %s (%d%% del total)", fee.Amount, fee.Percent)
+	}
+
+	return resp
+}
+
+// Transition devuelve un handler que lleva la cita al estado indicado
+func (h *AppointmentHandler) Transition(to domain.AppointmentStatus) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+			return
+		}
+
+		appt, err := h.svc.Transition(c.Request.Context(), uint(id), to)
+		if err != nil {
+			if err == domain.ErrorNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "cita no encontrada"})
+				return
+			}
+			if errors.Is(err, domain.ErrorInvalidTransition) || errors.Is(err, domain.ErrorDepositRequired) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, appt)
+	}
+}
+
+// PayDeposit registra el pago de la sena exigida por inasistencias
+func (h *AppointmentHandler) PayDeposit(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
@@ -163,16 +454,21 @@ func (h *AppointmentHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.svc.Cancel(c.Request.Context(), uint(id)); err != nil {
+	appt, err := h.svc.PayDeposit(c.Request.Context(), uint(id))
+	if err != nil {
 		if err == domain.ErrorNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "cita no encontrada"})
 			return
 		}
+		if errors.Is(err, domain.ErrorInvalidInput) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "cita cancelada exitosamente"})
+	c.JSON(http.StatusOK, appt)
 }
 
 func (h *AppointmentHandler) GetTotal(c *gin.Context) {
@@ -193,8 +489,5 @@ func (h *AppointmentHandler) GetTotal(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{
-		"appoinmet_id": id,
-		"total":        total,
-	})
+	c.JSON(http.StatusOK, total)
 }
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"github.com/alexnt4/barber-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type AvailabilityHandler struct {
+	svc *service.AvailabilityService
+}
+
+func NewAvailabilityHandler(svc *service.AvailabilityService) *AvailabilityHandler {
+	return &AvailabilityHandler{svc}
+}
+
+func (h *AvailabilityHandler) Search(c *gin.Context) {
+	// Parseo de la fecha, en la zona horaria de la barberia
+	date, err := time.ParseInLocation("2006-01-02", c.Query("date"), h.svc.Location())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "formato de fecha invalido para date, use YYYY-MM-DD"})
+		return
+	}
+
+	// Los productos se aceptan separados por coma o repitiendo el parametro
+	var productIDs []uint
+	for _, value := range c.QueryArray("products") {
+		for _, idStr := range strings.Split(value, ",") {
+			id, err := strconv.ParseUint(strings.TrimSpace(idStr), 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID de producto invalido"})
+				return
+			}
+			productIDs = append(productIDs, uint(id))
+		}
+	}
+
+	if len(productIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "debe indicar al menos un producto"})
+		return
+	}
+
+	var barberID uint64
+	if barberStr := c.Query("barber"); barberStr != "" {
+		barberID, err = strconv.ParseUint(barberStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID de barbero invalido"})
+			return
+		}
+	}
+
+	slots, err := h.svc.FindSlots(c.Request.Context(), date, productIDs, uint(barberID))
+	if err != nil {
+		// Producto o barbero inexistente
+		if errors.Is(err, domain.ErrorNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"date":  date.Format("2006-01-02"),
+		"slots": slots,
+		"total": len(slots),
+	})
+}
@@ -0,0 +1,140 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"github.com/alexnt4/barber-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type BarberHandler struct {
+	svc *service.BarberService
+}
+
+func NewBarberHandler(svc *service.BarberService) *BarberHandler {
+	return &BarberHandler{svc}
+}
+
+type CreateBarberRequest struct {
+	Name   string `json:"name" binding:"required"`
+	Phone  string `json:"phone"`
+	Active *bool  `json:"active"`
+}
+
+type UpdateBarberRequest struct {
+	Name   string `json:"name" binding:"required"`
+	Phone  string `json:"phone"`
+	Active *bool  `json:"active"`
+}
+
+func (h *BarberHandler) Create(c *gin.Context) {
+	var req CreateBarberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	barber := &domain.Barber{
+		Name:   req.Name,
+		Phone:  req.Phone,
+		Active: req.Active == nil || *req.Active,
+	}
+
+	if err := h.svc.Create(c.Request.Context(), barber); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, barber)
+}
+
+func (h *BarberHandler) List(c *gin.Context) {
+	barbers, err := h.svc.ListAll(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"barbers": barbers,
+		"total":   len(barbers),
+	})
+}
+
+func (h *BarberHandler) Get(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	barber, err := h.svc.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "barbero no encontrado"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, barber)
+}
+
+func (h *BarberHandler) Update(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	var req UpdateBarberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	barber := &domain.Barber{
+		Name:  req.Name,
+		Phone: req.Phone,
+	}
+
+	if err := h.svc.Update(c.Request.Context(), uint(id), barber, req.Active); err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "barbero no encontrado"})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, barber)
+}
+
+func (h *BarberHandler) Delete(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	if err := h.svc.Delete(c.Request.Context(), uint(id)); err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "barbero no encontrado"})
+			return
+		}
+		if err == domain.ErrorBarberHasAppts {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "barbero eliminado exitosamente"})
+}
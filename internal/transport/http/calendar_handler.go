@@ -0,0 +1,210 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"github.com/alexnt4/barber-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type CalendarHandler struct {
+	svc *service.CalendarService
+}
+
+func NewCalendarHandler(svc *service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{svc}
+}
+
+type SetWeeklyHoursRequest struct {
+	Closed    bool   `json:"closed"`
+	OpenTime  string `json:"open_time"`
+	CloseTime string `json:"close_time"`
+}
+
+type CalendarExceptionRequest struct {
+	Date      string `json:"date" binding:"required"`
+	Kind      string `json:"kind" binding:"required"`
+	OpenTime  string `json:"open_time"`
+	CloseTime string `json:"close_time"`
+	Reason    string `json:"reason"`
+}
+
+func (h *CalendarHandler) ListWeeklyHours(c *gin.Context) {
+	hours, err := h.svc.ListWeeklyHours(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"hours": hours})
+}
+
+func (h *CalendarHandler) SetWeeklyHours(c *gin.Context) {
+	weekday, err := strconv.Atoi(c.Param("weekday"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "dia de la semana invalido"})
+		return
+	}
+
+	var req SetWeeklyHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hours := &domain.BusinessHours{
+		Weekday:   weekday,
+		Closed:    req.Closed,
+		OpenTime:  req.OpenTime,
+		CloseTime: req.CloseTime,
+	}
+
+	if err := h.svc.SetWeeklyHours(c.Request.Context(), hours); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, hours)
+}
+
+func (h *CalendarHandler) CreateException(c *gin.Context) {
+	var req CalendarExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	exc, err := req.toDomain()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "formato de fecha invalido para date, use YYYY-MM-DD"})
+		return
+	}
+
+	if err := h.svc.CreateException(c.Request.Context(), exc); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, exc)
+}
+
+func (h *CalendarHandler) ListExceptions(c *gin.Context) {
+	var from, to time.Time
+	var err error
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		if from, err = time.Parse("2006-01-02", fromStr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "formato de fecha invalido para from, use YYYY-MM-DD"})
+			return
+		}
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		if to, err = time.Parse("2006-01-02", toStr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "formato de fecha invalido para to, use YYYY-MM-DD"})
+			return
+		}
+	}
+
+	excs, err := h.svc.ListExceptions(c.Request.Context(), from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"exceptions": excs,
+		"total":      len(excs),
+	})
+}
+
+func (h *CalendarHandler) GetException(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	exc, err := h.svc.GetException(c.Request.Context(), uint(id))
+	if err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "excepcion no encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, exc)
+}
+
+func (h *CalendarHandler) UpdateException(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	var req CalendarExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	exc, err := req.toDomain()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "formato de fecha invalido para date, use YYYY-MM-DD"})
+		return
+	}
+
+	if err := h.svc.UpdateException(c.Request.Context(), uint(id), exc); err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "excepcion no encontrada"})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, exc)
+}
+
+func (h *CalendarHandler) DeleteException(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	if err := h.svc.DeleteException(c.Request.Context(), uint(id)); err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "excepcion no encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "excepcion eliminada exitosamente"})
+}
+
+func (req *CalendarExceptionRequest) toDomain() (*domain.CalendarException, error) {
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.CalendarException{
+		Date:      date,
+		Kind:      req.Kind,
+		OpenTime:  req.OpenTime,
+		CloseTime: req.CloseTime,
+		Reason:    req.Reason,
+	}, nil
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// Export devuelve todo lo guardado del cliente, en JSON o como ZIP con un
+// archivo por seccion usando format=zip
+func (h *ClientHandler) Export(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	export, err := h.svc.Export(c.Request.Context(), uint(id))
+	if err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "cliente no encontrado"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=cliente-%d.json", id))
+		c.JSON(http.StatusOK, export)
+	case "zip":
+		data, err := exportZip(export)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=cliente-%d.zip", id))
+		c.Data(http.StatusOK, "application/zip", data)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format solo admite json o zip"})
+	}
+}
+
+// exportZip arma un ZIP con un archivo JSON por cada seccion de la exportacion
+func exportZip(export *domain.ClientExport) ([]byte, error) {
+	files := []struct {
+		name string
+		data any
+	}{
+		{"cliente.json", export.Client},
+		{"citas.json", export.Appointments},
+		{"lista_espera.json", export.Waitlist},
+		{"cargos.json", export.Fees},
+		{"puntos.json", export.Loyalty},
+		{"promociones.json", export.Promotions},
+		{"tarjetas_regalo.json", export.Vouchers},
+		{"recibos.json", export.Receipts},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(file.data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Erase anonimiza los datos personales del cliente conservando sus citas y cargos
+func (h *ClientHandler) Erase(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	client, err := h.svc.Erase(c.Request.Context(), uint(id))
+	if err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "cliente no encontrado"})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "datos del cliente eliminados exitosamente",
+		"client":  client,
+	})
+}
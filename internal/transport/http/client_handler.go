@@ -0,0 +1,272 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"github.com/alexnt4/barber-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ClientHandler struct {
+	svc *service.ClientService
+}
+
+func NewClientHandler(svc *service.ClientService) *ClientHandler {
+	return &ClientHandler{svc}
+}
+
+type CreateClientRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Phone    string `json:"phone"`
+	Email    string `json:"email"`
+	Birthday string `json:"birthday"`
+	Notes    string `json:"notes"`
+}
+
+type UpdateClientRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Phone    string `json:"phone"`
+	Email    string `json:"email"`
+	Birthday string `json:"birthday"`
+	Notes    string `json:"notes"`
+}
+
+type MergeClientRequest struct {
+	DuplicateID uint `json:"duplicate_id" binding:"required"`
+}
+
+func (h *ClientHandler) Create(c *gin.Context) {
+	var req CreateClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	birthday, err := parseBirthday(req.Birthday)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "formato de fecha invalido para birthday, use YYYY-MM-DD"})
+		return
+	}
+
+	client := &domain.Client{
+		Name:     req.Name,
+		Phone:    req.Phone,
+		Email:    req.Email,
+		Birthday: birthday,
+		Notes:    req.Notes,
+	}
+
+	if err := h.svc.Create(c.Request.Context(), client); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, client)
+}
+
+func (h *ClientHandler) List(c *gin.Context) {
+	clients, err := h.svc.List(c.Request.Context(), c.Query("q"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"clients": clients,
+		"total":   len(clients),
+	})
+}
+
+func (h *ClientHandler) Get(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	client, err := h.svc.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "cliente no encontrado"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, client)
+}
+
+func (h *ClientHandler) Update(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	var req UpdateClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	birthday, err := parseBirthday(req.Birthday)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "formato de fecha invalido para birthday, use YYYY-MM-DD"})
+		return
+	}
+
+	client := &domain.Client{
+		Name:     req.Name,
+		Phone:    req.Phone,
+		Email:    req.Email,
+		Birthday: birthday,
+		Notes:    req.Notes,
+	}
+
+	if err := h.svc.Update(c.Request.Context(), uint(id), client); err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "cliente no encontrado"})
+			return
+		}
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, client)
+}
+
+func (h *ClientHandler) Delete(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	if err := h.svc.Delete(c.Request.Context(), uint(id)); err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "cliente no encontrado"})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "cliente eliminado exitosamente"})
+}
+
+func (h *ClientHandler) History(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	history, err := h.svc.History(c.Request.Context(), uint(id))
+	if err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "cliente no encontrado"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *ClientHandler) Duplicates(c *gin.Context) {
+	minScore := service.DefaultDuplicateScore
+	if scoreStr := c.Query("min_score"); scoreStr != "" {
+		score, err := strconv.ParseFloat(scoreStr, 64)
+		if err != nil || score <= 0 || score > 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "min_score debe ser un numero entre 0 y 1"})
+			return
+		}
+		minScore = score
+	}
+
+	candidates, err := h.svc.FindDuplicates(c.Request.Context(), minScore)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"duplicates": candidates,
+		"total":      len(candidates),
+	})
+}
+
+// Merge fusiona el cliente duplicado en el cliente de la URL, que sobrevive
+func (h *ClientHandler) Merge(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	var req MergeClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	client, err := h.svc.Merge(c.Request.Context(), uint(id), req.DuplicateID)
+	if err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "cliente no encontrado"})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, client)
+}
+
+// parseBirthday convierte la fecha de cumpleaños opcional (YYYY-MM-DD)
+func parseBirthday(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	birthday, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &birthday, nil
+}
+
+func (h *ClientHandler) Fees(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	fees, err := h.svc.Fees(c.Request.Context(), uint(id))
+	if err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "cliente no encontrado"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"fees":  fees,
+		"total": len(fees),
+	})
+}
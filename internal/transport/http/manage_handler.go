@@ -0,0 +1,109 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"github.com/alexnt4/barber-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ManageHandler atiende los enlaces publicos con los que un cliente consulta,
+// reprograma o cancela su propia cita usando el token recibido al agendar
+type ManageHandler struct {
+	svc *service.AppointmentService
+}
+
+func NewManageHandler(svc *service.AppointmentService) *ManageHandler {
+	return &ManageHandler{svc}
+}
+
+type RescheduleRequest struct {
+	StartTime string `json:"start_time" binding:"required"`
+}
+
+// publicAppointment expone solo los datos de la cita que el cliente necesita,
+// sin notas internas ni datos de contacto
+func publicAppointment(appt *domain.Appointment) gin.H {
+	services := make([]string, len(appt.Items))
+	for i, item := range appt.Items {
+		services[i] = item.Name
+	}
+
+	view := gin.H{
+		"id":         appt.ID,
+		"start_time": appt.StartTime,
+		"end_time":   appt.EndTime,
+		"status":     appt.Status,
+		"services":   services,
+	}
+	if appt.Barber != nil {
+		view["barber"] = appt.Barber.Name
+	}
+	if appt.ManageToken != "" {
+		view["manage_token"] = appt.ManageToken
+	}
+
+	return view
+}
+
+func (h *ManageHandler) Get(c *gin.Context) {
+	appt, err := h.svc.GetByManageToken(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, publicAppointment(appt))
+}
+
+func (h *ManageHandler) Reschedule(c *gin.Context) {
+	var req RescheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	startTime, err := time.Parse(time.RFC3339, req.StartTime)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "formato de fecha invalido para start_time, use RFC3339"})
+		return
+	}
+
+	appt, err := h.svc.RescheduleByToken(c.Request.Context(), c.Param("token"), startTime)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	// Se recarga la cita para devolver barbero y servicios completos
+	if full, err := h.svc.GetById(c.Request.Context(), appt.ID); err == nil {
+		full.ManageToken = appt.ManageToken
+		appt = full
+	}
+
+	c.JSON(http.StatusOK, publicAppointment(appt))
+}
+
+func (h *ManageHandler) Cancel(c *gin.Context) {
+	appt, fee, err := h.svc.CancelByToken(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cancelResponse(publicAppointment(appt), fee))
+}
+
+func (h *ManageHandler) writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrorInvalidToken):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case err == domain.ErrorNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "cita no encontrada"})
+	default:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	}
+}
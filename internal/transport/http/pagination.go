@@ -0,0 +1,64 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// parseLimit lee el parametro limit aplicando el valor por defecto y el maximo
+func parseLimit(c *gin.Context) (int, error) {
+	limitStr := c.Query("limit")
+	if limitStr == "" {
+		return defaultPageLimit, nil
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 1 || limit > maxPageLimit {
+		return 0, errors.New("limit debe ser un entero entre 1 y 200")
+	}
+
+	return limit, nil
+}
+
+// parseSort separa el parametro sort en columna y direccion: "-price"
+// ordena por precio de forma descendente
+func parseSort(value string) (column string, desc bool) {
+	if strings.HasPrefix(value, "-") {
+		return value[1:], true
+	}
+
+	return value, false
+}
+
+// parseQueryTime acepta una fecha (YYYY-MM-DD) o una fecha y hora en RFC3339
+func parseQueryTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.ParseInLocation("2006-01-02", value, time.Local)
+}
+
+// parseQueryID lee un parametro opcional con un ID, cero si no se envio
+func parseQueryID(c *gin.Context, name string) (uint, error) {
+	idStr := c.Query(name)
+	if idStr == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		return 0, errors.New(name + " invalido")
+	}
+
+	return uint(id), nil
+}
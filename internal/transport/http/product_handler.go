@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -18,15 +19,29 @@ func NewProductHandler(svc *service.ProductService) *ProductHandler {
 }
 
 type CreateProductRequest struct {
-	Name        string  `json:"name" binding:"required"`
-	Price       float64 `json:"price" binding:"required,gt=0"`
-	Description string  `json:"description"`
+	Name            string `json:"name" binding:"required"`
+	PriceCents      int64  `json:"price_cents" binding:"required,gt=0"`
+	Currency        string `json:"currency"`
+	Description     string `json:"description"`
+	Category        string `json:"category"`
+	TaxRateBps      *int   `json:"tax_rate_bps"`
+	TaxIncluded     *bool  `json:"tax_included"`
+	TaxExempt       bool   `json:"tax_exempt"`
+	DurationMinutes int    `json:"duration_minutes" binding:"gte=0"`
+	BufferMinutes   int    `json:"buffer_minutes" binding:"gte=0"`
 }
 
 type UpdateProductRequest struct {
-	Name        string  `json:"name" binding:"required"`
-	Price       float64 `json:"price" binding:"required,gt=0"`
-	Description string  `json:"description"`
+	Name            string `json:"name" binding:"required"`
+	PriceCents      int64  `json:"price_cents" binding:"required,gt=0"`
+	Currency        string `json:"currency"`
+	Description     string `json:"description"`
+	Category        string `json:"category"`
+	TaxRateBps      *int   `json:"tax_rate_bps"`
+	TaxIncluded     *bool  `json:"tax_included"`
+	TaxExempt       bool   `json:"tax_exempt"`
+	DurationMinutes int    `json:"duration_minutes" binding:"gte=0"`
+	BufferMinutes   int    `json:"buffer_minutes" binding:"gte=0"`
 }
 
 func (h *ProductHandler) Create(c *gin.Context) {
@@ -37,9 +52,13 @@ func (h *ProductHandler) Create(c *gin.Context) {
 	}
 
 	product := &domain.Product{
-		Name:        req.Name,
-		Price:       req.Price,
-		Description: req.Description,
+		Name:            req.Name,
+		Price:           domain.NewMoney(req.PriceCents, req.Currency),
+		Description:     req.Description,
+		Category:        req.Category,
+		Tax:             requestTax(req.TaxRateBps, req.TaxIncluded, req.TaxExempt),
+		DurationMinutes: req.DurationMinutes,
+		BufferMinutes:   req.BufferMinutes,
 	}
 
 	if err := h.svc.Create(c.Request.Context(), product); err != nil {
@@ -51,15 +70,36 @@ func (h *ProductHandler) Create(c *gin.Context) {
 }
 
 func (h *ProductHandler) List(c *gin.Context) {
-	products, err := h.svc.ListAll(c.Request.Context())
+	limit, err := parseLimit(c)
 	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sortBy, desc := parseSort(c.Query("sort"))
+	opts := domain.ProductListOptions{
+		Name:     c.Query("name"),
+		Category: c.Query("category"),
+		SortBy:   sortBy,
+		SortDesc: desc,
+		Cursor:   c.Query("cursor"),
+		Limit:    limit,
+	}
+
+	products, nextCursor, err := h.svc.List(c.Request.Context(), opts)
+	if err != nil {
+		if errors.Is(err, domain.ErrorInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"products": products,
-		"total":    len(products),
+		"products":    products,
+		"total":       len(products),
+		"next_cursor": nextCursor,
 	})
 }
 
@@ -99,9 +139,13 @@ func (h *ProductHandler) Update(c *gin.Context) {
 	}
 
 	product := &domain.Product{
-		Name:        req.Name,
-		Price:       req.Price,
-		Description: req.Description,
+		Name:            req.Name,
+		Price:           domain.NewMoney(req.PriceCents, req.Currency),
+		Description:     req.Description,
+		Category:        req.Category,
+		Tax:             requestTax(req.TaxRateBps, req.TaxIncluded, req.TaxExempt),
+		DurationMinutes: req.DurationMinutes,
+		BufferMinutes:   req.BufferMinutes,
 	}
 
 	if err := h.svc.Update(c.Request.Context(), uint(id), product); err != nil {
@@ -131,3 +175,21 @@ func (h *ProductHandler) Delete(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "prducto cancelada exitosamente"})
 }
+
+// requestTax arma el impuesto indicado en la solicitud, completando con el
+// impuesto por defecto de la barberia lo que no se envio. Un producto exento
+// no toma la tarifa por defecto.
+func requestTax(rateBps *int, included *bool, exempt bool) domain.Tax {
+	tax := domain.DefaultTax
+	tax.Exempt = exempt
+	if exempt {
+		tax.Rate = 0
+	}
+	if rateBps != nil {
+		tax.Rate = *rateBps
+	}
+	if included != nil {
+		tax.Included = *included
+	}
+	return tax
+}
@@ -0,0 +1,170 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"github.com/alexnt4/barber-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PromotionHandler struct {
+	svc *service.PromotionService
+}
+
+func NewPromotionHandler(svc *service.PromotionService) *PromotionHandler {
+	return &PromotionHandler{svc}
+}
+
+type CreatePromotionRequest struct {
+	Code             string `json:"code" binding:"required"`
+	Description      string `json:"description"`
+	Kind             string `json:"kind" binding:"required"`
+	Percent          int    `json:"percent"`
+	AmountCents      int64  `json:"amount_cents"`
+	Currency         string `json:"currency"`
+	ProductID        *uint  `json:"product_id"`
+	Category         string `json:"category"`
+	ValidFrom        string `json:"valid_from"`
+	ValidUntil       string `json:"valid_until"`
+	Weekdays         []int  `json:"weekdays"`
+	MaxUses          int    `json:"max_uses" binding:"gte=0"`
+	MaxUsesPerClient int    `json:"max_uses_per_client" binding:"gte=0"`
+}
+
+type ApplyPromotionRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+func (h *PromotionHandler) Create(c *gin.Context) {
+	var req CreatePromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	promo := &domain.Promotion{
+		Code:             req.Code,
+		Description:      req.Description,
+		Kind:             domain.DiscountKind(req.Kind),
+		Percent:          req.Percent,
+		Amount:           domain.NewMoney(req.AmountCents, req.Currency),
+		ProductID:        req.ProductID,
+		Category:         req.Category,
+		MaxUses:          req.MaxUses,
+		MaxUsesPerClient: req.MaxUsesPerClient,
+	}
+
+	for _, day := range req.Weekdays {
+		promo.Weekdays = append(promo.Weekdays, time.Weekday(day))
+	}
+
+	if req.ValidFrom != "" {
+		validFrom, err := parseQueryTime(req.ValidFrom)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "formato de fecha invalido para valid_from, use YYYY-MM-DD o RFC3339"})
+			return
+		}
+		promo.ValidFrom = &validFrom
+	}
+
+	// Una fecha sin hora vale hasta el final de ese dia
+	if req.ValidUntil != "" {
+		validUntil, err := parseQueryTime(req.ValidUntil)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "formato de fecha invalido para valid_until, use YYYY-MM-DD o RFC3339"})
+			return
+		}
+		if len(req.ValidUntil) == len("2006-01-02") {
+			validUntil = validUntil.AddDate(0, 0, 1)
+		}
+		promo.ValidUntil = &validUntil
+	}
+
+	if err := h.svc.Create(c.Request.Context(), promo); err != nil {
+		if errors.Is(err, domain.ErrorInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, domain.ErrorDuplicatePromo) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, promo)
+}
+
+func (h *PromotionHandler) List(c *gin.Context) {
+	promos, err := h.svc.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"promotions": promos,
+		"total":      len(promos),
+	})
+}
+
+func (h *PromotionHandler) Get(c *gin.Context) {
+	promo, err := h.svc.GetByCode(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "promocion no encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, promo)
+}
+
+func (h *PromotionHandler) Deactivate(c *gin.Context) {
+	promo, err := h.svc.Deactivate(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "promocion no encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, promo)
+}
+
+// Apply aplica un codigo promocional a una cita ya agendada
+func (h *PromotionHandler) Apply(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	var req ApplyPromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	redemption, err := h.svc.Apply(c.Request.Context(), uint(id), req.Code)
+	if err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "cita o promocion no encontrada"})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, redemption)
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"github.com/alexnt4/barber-api/internal/receipt"
+	"github.com/alexnt4/barber-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ReceiptHandler struct {
+	svc *service.ReceiptService
+}
+
+func NewReceiptHandler(svc *service.ReceiptService) *ReceiptHandler {
+	return &ReceiptHandler{svc}
+}
+
+// Get devuelve el recibo de la cita en HTML (por defecto) o PDF segun el
+// parametro format
+func (h *ReceiptHandler) Get(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	format := c.DefaultQuery("format", "html")
+	if format != "html" && format != "pdf" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format solo admite html o pdf"})
+		return
+	}
+
+	doc, err := h.svc.Get(c.Request.Context(), uint(id))
+	if err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "cita no encontrada"})
+			return
+		}
+		if err == domain.ErrorNotCompleted {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var buf bytes.Buffer
+	if format == "pdf" {
+		err = receipt.RenderPDF(&buf, doc)
+	} else {
+		err = receipt.RenderHTML(&buf, doc)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if format == "pdf" {
+		c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%s", doc.Filename("pdf")))
+		c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
@@ -1,11 +1,12 @@
 package http
 
 import (
+	"github.com/alexnt4/barber-api/internal/domain"
 	"github.com/alexnt4/barber-api/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
-func NewRouter(apptSvc *service.AppointmentService, prodSvc *service.ProductService) *gin.Engine {
+func NewRouter(apptSvc *service.AppointmentService, prodSvc *service.ProductService, barberSvc *service.BarberService, availSvc *service.AvailabilityService, calendarSvc *service.CalendarService, waitlistSvc *service.WaitlistService, clientSvc *service.ClientService, loyaltySvc *service.LoyaltyService, voucherSvc *service.VoucherService, promoSvc *service.PromotionService, receiptSvc *service.ReceiptService) *gin.Engine {
 	r := gin.Default()
 
 	// Middleware de CORS basico
@@ -31,16 +32,34 @@ func NewRouter(apptSvc *service.AppointmentService, prodSvc *service.ProductServ
 	// API v1
 	v1 := r.Group("/api/v1")
 	{
+		loyaltyHandler := NewLoyaltyHandler(loyaltySvc)
+		voucherHandler := NewVoucherHandler(voucherSvc)
+		promoHandler := NewPromotionHandler(promoSvc)
+		receiptHandler := NewReceiptHandler(receiptSvc)
+
 		// Apointments routes
 		appts := v1.Group("/appointments")
 		{
-			apptHandler := NewAppoinmentHandler(apptSvc)
+			apptHandler := NewAppoinmentHandler(apptSvc, promoSvc)
 			appts.POST("", apptHandler.Create)
 			appts.GET("", apptHandler.List)
 			appts.GET("/:id", apptHandler.Get)
 			appts.PUT("/:id", apptHandler.Update)
-			appts.DELETE("/:id", apptHandler.Delete)
+			appts.DELETE("/:id", apptHandler.Cancel)
 			appts.GET("/:id/total", apptHandler.GetTotal)
+			appts.GET("/:id/receipt", receiptHandler.Get)
+
+			// Cambios de estado
+			appts.POST("/:id/confirm", apptHandler.Transition(domain.StatusConfirmed))
+			appts.POST("/:id/check-in", apptHandler.Transition(domain.StatusCheckedIn))
+			appts.POST("/:id/start", apptHandler.Transition(domain.StatusInProgress))
+			appts.POST("/:id/complete", apptHandler.Transition(domain.StatusCompleted))
+			appts.POST("/:id/no-show", apptHandler.Transition(domain.StatusNoShow))
+			appts.POST("/:id/cancel", apptHandler.Cancel)
+			appts.POST("/:id/deposit", apptHandler.PayDeposit)
+			appts.POST("/:id/loyalty/redeem", loyaltyHandler.Redeem)
+			appts.POST("/:id/vouchers", voucherHandler.Redeem)
+			appts.POST("/:id/promotions", promoHandler.Apply)
 		}
 
 		// Product routes
@@ -53,6 +72,90 @@ func NewRouter(apptSvc *service.AppointmentService, prodSvc *service.ProductServ
 			products.PUT("/:id", prodHandler.Update)
 			products.DELETE("/:id", prodHandler.Delete)
 		}
+
+		// Barber routes
+		barbers := v1.Group("/barbers")
+		{
+			barberHandler := NewBarberHandler(barberSvc)
+			barbers.POST("", barberHandler.Create)
+			barbers.GET("", barberHandler.List)
+			barbers.GET("/:id", barberHandler.Get)
+			barbers.PUT("/:id", barberHandler.Update)
+			barbers.DELETE("/:id", barberHandler.Delete)
+		}
+
+		// Client routes
+		clients := v1.Group("/clients")
+		{
+			clientHandler := NewClientHandler(clientSvc)
+			clients.POST("", clientHandler.Create)
+			clients.GET("", clientHandler.List)
+			clients.GET("/duplicates", clientHandler.Duplicates)
+			clients.GET("/:id", clientHandler.Get)
+			clients.PUT("/:id", clientHandler.Update)
+			clients.DELETE("/:id", clientHandler.Delete)
+			clients.POST("/:id/merge", clientHandler.Merge)
+			clients.GET("/:id/history", clientHandler.History)
+			clients.GET("/:id/fees", clientHandler.Fees)
+			clients.GET("/:id/export", clientHandler.Export)
+			clients.POST("/:id/erase", clientHandler.Erase)
+			clients.GET("/:id/loyalty", loyaltyHandler.Balance)
+			clients.GET("/:id/loyalty/history", loyaltyHandler.History)
+		}
+
+		// Availability routes
+		availHandler := NewAvailabilityHandler(availSvc)
+		v1.GET("/availability", availHandler.Search)
+
+		// Calendar routes
+		calendar := v1.Group("/calendar")
+		{
+			calendarHandler := NewCalendarHandler(calendarSvc)
+			calendar.GET("/hours", calendarHandler.ListWeeklyHours)
+			calendar.PUT("/hours/:weekday", calendarHandler.SetWeeklyHours)
+			calendar.POST("/exceptions", calendarHandler.CreateException)
+			calendar.GET("/exceptions", calendarHandler.ListExceptions)
+			calendar.GET("/exceptions/:id", calendarHandler.GetException)
+			calendar.PUT("/exceptions/:id", calendarHandler.UpdateException)
+			calendar.DELETE("/exceptions/:id", calendarHandler.DeleteException)
+		}
+
+		// Waitlist routes
+		waitlist := v1.Group("/waitlist")
+		{
+			waitlistHandler := NewWaitlistHandler(waitlistSvc)
+			waitlist.POST("", waitlistHandler.Join)
+			waitlist.GET("", waitlistHandler.List)
+			waitlist.GET("/:id", waitlistHandler.Get)
+			waitlist.DELETE("/:id", waitlistHandler.Leave)
+			waitlist.POST("/:id/accept", waitlistHandler.Accept)
+			waitlist.POST("/:id/decline", waitlistHandler.Decline)
+		}
+
+		// Voucher routes
+		vouchers := v1.Group("/vouchers")
+		{
+			vouchers.POST("", voucherHandler.Issue)
+			vouchers.GET("/:code", voucherHandler.Get)
+		}
+
+		// Promotion routes
+		promotions := v1.Group("/promotions")
+		{
+			promotions.POST("", promoHandler.Create)
+			promotions.GET("", promoHandler.List)
+			promotions.GET("/:code", promoHandler.Get)
+			promotions.POST("/:code/deactivate", promoHandler.Deactivate)
+		}
+
+		// Enlaces publicos para que el cliente administre su cita
+		manage := v1.Group("/public/appointments")
+		{
+			manageHandler := NewManageHandler(apptSvc)
+			manage.GET("/:token", manageHandler.Get)
+			manage.POST("/:token/reschedule", manageHandler.Reschedule)
+			manage.POST("/:token/cancel", manageHandler.Cancel)
+		}
 	}
 
 	return r
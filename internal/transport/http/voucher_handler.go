@@ -0,0 +1,125 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"github.com/alexnt4/barber-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type VoucherHandler struct {
+	svc *service.VoucherService
+}
+
+func NewVoucherHandler(svc *service.VoucherService) *VoucherHandler {
+	return &VoucherHandler{svc}
+}
+
+type IssueVoucherRequest struct {
+	Code                string `json:"code"`
+	InitialBalanceCents int64  `json:"initial_balance_cents" binding:"required,gt=0"`
+	Currency            string `json:"currency"`
+	ExpiresAt           string `json:"expires_at"`
+}
+
+type RedeemVoucherRequest struct {
+	Code        string `json:"code" binding:"required"`
+	AmountCents int64  `json:"amount_cents" binding:"gte=0"`
+}
+
+func (h *VoucherHandler) Issue(c *gin.Context) {
+	var req IssueVoucherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	voucher := &domain.Voucher{
+		Code:           req.Code,
+		InitialBalance: domain.NewMoney(req.InitialBalanceCents, req.Currency),
+	}
+
+	// Una fecha sin hora vale hasta el final de ese dia
+	if req.ExpiresAt != "" {
+		expiresAt, err := parseQueryTime(req.ExpiresAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "formato de fecha invalido para expires_at, use YYYY-MM-DD o RFC3339"})
+			return
+		}
+		if len(req.ExpiresAt) == len("2006-01-02") {
+			expiresAt = expiresAt.AddDate(0, 0, 1)
+		}
+		voucher.ExpiresAt = &expiresAt
+	}
+
+	if err := h.svc.Issue(c.Request.Context(), voucher); err != nil {
+		if errors.Is(err, domain.ErrorInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, domain.ErrorDuplicateCode) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, voucher)
+}
+
+// Get permite consultar el saldo de una tarjeta por su codigo
+func (h *VoucherHandler) Get(c *gin.Context) {
+	voucher, err := h.svc.GetByCode(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "tarjeta de regalo no encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":       voucher.Code,
+		"balance":    voucher.Balance,
+		"expires_at": voucher.ExpiresAt,
+		"expired":    voucher.ExpiresAt != nil && !voucher.ExpiresAt.After(time.Now()),
+	})
+}
+
+// Redeem paga parte o todo el pendiente de la cita con una tarjeta
+func (h *VoucherHandler) Redeem(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	var req RedeemVoucherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	vtx, err := h.svc.Redeem(c.Request.Context(), req.Code, uint(id), req.AmountCents)
+	if err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "cita o tarjeta de regalo no encontrada"})
+			return
+		}
+		if errors.Is(err, domain.ErrorInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, vtx)
+}
@@ -0,0 +1,164 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alexnt4/barber-api/internal/domain"
+	"github.com/alexnt4/barber-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type WaitlistHandler struct {
+	svc *service.WaitlistService
+}
+
+func NewWaitlistHandler(svc *service.WaitlistService) *WaitlistHandler {
+	return &WaitlistHandler{svc}
+}
+
+type JoinWaitlistRequest struct {
+	ClientID    uint   `json:"client_id" binding:"required"`
+	BarberID    *uint  `json:"barber_id"`
+	WindowStart string `json:"window_start" binding:"required"`
+	WindowEnd   string `json:"window_end" binding:"required"`
+	Products    []uint `json:"products" binding:"required,min=1"`
+}
+
+func (h *WaitlistHandler) Join(c *gin.Context) {
+	var req JoinWaitlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Parseo de fechas
+	windowStart, err := time.Parse(time.RFC3339, req.WindowStart)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "formato de fecha invalido para window_start, use RFC3339"})
+		return
+	}
+
+	windowEnd, err := time.Parse(time.RFC3339, req.WindowEnd)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "formato de fecha invalido para window_end, use RFC3339"})
+		return
+	}
+
+	// Mapear IDs a domain.Product
+	products := make([]domain.Product, len(req.Products))
+	for i, id := range req.Products {
+		products[i] = domain.Product{ID: id}
+	}
+
+	entry := &domain.WaitlistEntry{
+		ClientID:    req.ClientID,
+		BarberID:    req.BarberID,
+		WindowStart: windowStart,
+		WindowEnd:   windowEnd,
+		Products:    products,
+	}
+
+	if err := h.svc.Join(c.Request.Context(), entry); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+func (h *WaitlistHandler) List(c *gin.Context) {
+	entries, err := h.svc.List(c.Request.Context(), domain.WaitlistStatus(c.Query("status")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"waitlist": entries,
+		"total":    len(entries),
+	})
+}
+
+func (h *WaitlistHandler) Get(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	entry, err := h.svc.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "solicitud no encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+func (h *WaitlistHandler) Leave(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	if err := h.svc.Leave(c.Request.Context(), uint(id)); err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "solicitud no encontrada"})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "cliente retirado de la lista de espera"})
+}
+
+func (h *WaitlistHandler) Accept(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	appt, err := h.svc.Accept(c.Request.Context(), uint(id))
+	if err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "solicitud no encontrada"})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, appt)
+}
+
+func (h *WaitlistHandler) Decline(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalido"})
+		return
+	}
+
+	if err := h.svc.Decline(c.Request.Context(), uint(id)); err != nil {
+		if err == domain.ErrorNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "solicitud no encontrada"})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "oferta rechazada, el cliente sigue en lista de espera"})
+}
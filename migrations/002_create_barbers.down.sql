@@ -0,0 +1,6 @@
+-- Eliminar indice
+DROP INDEX IF EXISTS idx_appointments_barber_start_time;
+-- Quitar relacion de citas con barberos
+ALTER TABLE appointments DROP COLUMN IF EXISTS barber_id;
+-- Eliminar tabla de barberos
+DROP TABLE IF EXISTS barbers;
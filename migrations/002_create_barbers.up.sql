@@ -0,0 +1,22 @@
+-- Crear tabla de barberos
+CREATE TABLE barbers (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR(100) NOT NULL,
+  phone VARCHAR(30),
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Barbero por defecto para las citas que ya existen
+INSERT INTO barbers (name)
+SELECT 'Barbero principal'
+WHERE EXISTS (SELECT 1 FROM appointments);
+
+-- Asociar cada cita a un barbero
+ALTER TABLE appointments ADD COLUMN barber_id INTEGER REFERENCES barbers(id);
+UPDATE appointments SET barber_id = (SELECT MIN(id) FROM barbers) WHERE barber_id IS NULL;
+ALTER TABLE appointments ALTER COLUMN barber_id SET NOT NULL;
+
+-- Indice para buscar turnos por barbero
+CREATE INDEX idx_appointments_barber_start_time ON appointments(barber_id, start_time);
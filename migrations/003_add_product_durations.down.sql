@@ -0,0 +1,3 @@
+-- Eliminar columnas de duracion
+ALTER TABLE products DROP COLUMN IF EXISTS buffer_minutes;
+ALTER TABLE products DROP COLUMN IF EXISTS duration_minutes;
@@ -0,0 +1,7 @@
+-- Duracion de cada servicio y tiempo de limpieza posterior, en minutos
+ALTER TABLE products ADD COLUMN duration_minutes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN buffer_minutes INTEGER NOT NULL DEFAULT 0;
+
+-- Duraciones para los productos de ejemplo
+UPDATE products SET duration_minutes = 45 WHERE name = 'Corte de Cabello';
+UPDATE products SET duration_minutes = 30 WHERE name = 'Corte de Barba';
@@ -0,0 +1,3 @@
+-- Eliminar tablas del calendario
+DROP TABLE IF EXISTS calendar_exceptions;
+DROP TABLE IF EXISTS business_hours;
@@ -0,0 +1,30 @@
+-- Horario semanal de atencion (0 = domingo)
+CREATE TABLE business_hours (
+  weekday SMALLINT PRIMARY KEY CHECK (weekday BETWEEN 0 AND 6),
+  closed BOOLEAN NOT NULL DEFAULT FALSE,
+  open_time VARCHAR(5),
+  close_time VARCHAR(5),
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Excepciones por fecha: festivos, cierres especiales y cambios de horario
+CREATE TABLE calendar_exceptions (
+  id SERIAL PRIMARY KEY,
+  date DATE NOT NULL UNIQUE,
+  kind VARCHAR(20) NOT NULL CHECK (kind IN ('holiday', 'closure', 'override')),
+  open_time VARCHAR(5),
+  close_time VARCHAR(5),
+  reason VARCHAR(200),
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Horario por defecto: lunes a sabado de 09:00 a 19:00, domingo cerrado
+INSERT INTO business_hours (weekday, closed, open_time, close_time) VALUES
+(0, TRUE, NULL, NULL),
+(1, FALSE, '09:00', '19:00'),
+(2, FALSE, '09:00', '19:00'),
+(3, FALSE, '09:00', '19:00'),
+(4, FALSE, '09:00', '19:00'),
+(5, FALSE, '09:00', '19:00'),
+(6, FALSE, '09:00', '19:00');
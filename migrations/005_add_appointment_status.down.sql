@@ -0,0 +1,10 @@
+-- Eliminar indice
+DROP INDEX IF EXISTS idx_appointments_status;
+-- Eliminar columnas de estado
+ALTER TABLE appointments DROP COLUMN IF EXISTS cancelled_at;
+ALTER TABLE appointments DROP COLUMN IF EXISTS no_show_at;
+ALTER TABLE appointments DROP COLUMN IF EXISTS completed_at;
+ALTER TABLE appointments DROP COLUMN IF EXISTS started_at;
+ALTER TABLE appointments DROP COLUMN IF EXISTS checked_in_at;
+ALTER TABLE appointments DROP COLUMN IF EXISTS confirmed_at;
+ALTER TABLE appointments DROP COLUMN IF EXISTS status;
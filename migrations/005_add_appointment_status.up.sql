@@ -0,0 +1,12 @@
+-- Estado de la cita y hora de cada cambio de estado
+ALTER TABLE appointments ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'scheduled'
+  CHECK (status IN ('scheduled', 'confirmed', 'checked_in', 'in_progress', 'completed', 'no_show', 'cancelled'));
+ALTER TABLE appointments ADD COLUMN confirmed_at TIMESTAMP;
+ALTER TABLE appointments ADD COLUMN checked_in_at TIMESTAMP;
+ALTER TABLE appointments ADD COLUMN started_at TIMESTAMP;
+ALTER TABLE appointments ADD COLUMN completed_at TIMESTAMP;
+ALTER TABLE appointments ADD COLUMN no_show_at TIMESTAMP;
+ALTER TABLE appointments ADD COLUMN cancelled_at TIMESTAMP;
+
+-- Indice para reportes por estado
+CREATE INDEX idx_appointments_status ON appointments(status);
@@ -0,0 +1,5 @@
+-- Quitar relacion de citas con series
+DROP INDEX IF EXISTS idx_appointments_series_id;
+ALTER TABLE appointments DROP COLUMN IF EXISTS series_id;
+-- Eliminar tabla de series
+DROP TABLE IF EXISTS appointment_series;
@@ -0,0 +1,11 @@
+-- Crear tabla de series de citas recurrentes
+CREATE TABLE appointment_series (
+  id SERIAL PRIMARY KEY,
+  rrule VARCHAR(200) NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Asociar citas a su serie
+ALTER TABLE appointments ADD COLUMN series_id INTEGER REFERENCES appointment_series(id) ON DELETE SET NULL;
+CREATE INDEX idx_appointments_series_id ON appointments(series_id);
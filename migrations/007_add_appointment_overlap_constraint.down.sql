@@ -0,0 +1,2 @@
+-- Eliminar restriccion de solapamiento
+ALTER TABLE appointments DROP CONSTRAINT IF EXISTS appointments_barber_no_overlap;
@@ -0,0 +1,8 @@
+-- Necesario para combinar igualdad de barbero y solapamiento de rangos en un indice GiST
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- Impide que dos citas activas del mismo barbero se solapen, incluso si
+-- llegan al mismo tiempo. Las citas canceladas liberan su horario.
+ALTER TABLE appointments ADD CONSTRAINT appointments_barber_no_overlap
+  EXCLUDE USING gist (barber_id WITH =, tsrange(start_time, end_time) WITH &&)
+  WHERE (status <> 'cancelled');
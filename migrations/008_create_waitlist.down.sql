@@ -0,0 +1,3 @@
+-- Eliminar tablas de lista de espera
+DROP TABLE IF EXISTS waitlist_products;
+DROP TABLE IF EXISTS waitlist_entries;
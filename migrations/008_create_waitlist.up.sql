@@ -0,0 +1,30 @@
+-- Crear tabla de lista de espera
+CREATE TABLE waitlist_entries (
+  id SERIAL PRIMARY KEY,
+  client_name VARCHAR(100) NOT NULL,
+  phone VARCHAR(30),
+  barber_id INTEGER REFERENCES barbers(id),
+  window_start TIMESTAMP NOT NULL,
+  window_end TIMESTAMP NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'waiting'
+    CHECK (status IN ('waiting', 'offered', 'accepted', 'cancelled')),
+  offer_barber_id INTEGER REFERENCES barbers(id),
+  offer_start TIMESTAMP,
+  offer_end TIMESTAMP,
+  hold_expires_at TIMESTAMP,
+  appointment_id INTEGER REFERENCES appointments(id) ON DELETE SET NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear tabla de relacion many-to-many entre lista de espera y productos
+CREATE TABLE waitlist_products (
+  waitlist_entry_id INTEGER REFERENCES waitlist_entries(id) ON DELETE CASCADE,
+  product_id INTEGER REFERENCES products(id) ON DELETE CASCADE,
+  PRIMARY KEY (waitlist_entry_id, product_id)
+);
+
+-- Indices para buscar candidatos y reservas vigentes
+CREATE INDEX idx_waitlist_entries_status ON waitlist_entries(status);
+CREATE INDEX idx_waitlist_entries_window ON waitlist_entries(window_start, window_end);
+CREATE INDEX idx_waitlist_entries_barber_id ON waitlist_entries(barber_id);
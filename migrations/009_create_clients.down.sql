@@ -0,0 +1,19 @@
+-- Restaurar nombre y telefono en la lista de espera
+ALTER TABLE waitlist_entries ADD COLUMN client_name VARCHAR(100);
+ALTER TABLE waitlist_entries ADD COLUMN phone VARCHAR(30);
+UPDATE waitlist_entries w SET client_name = c.name, phone = c.phone
+FROM clients c WHERE w.client_id = c.id;
+ALTER TABLE waitlist_entries ALTER COLUMN client_name SET NOT NULL;
+DROP INDEX IF EXISTS idx_waitlist_entries_client_id;
+ALTER TABLE waitlist_entries DROP COLUMN client_id;
+
+-- Restaurar nombre del cliente en las citas
+ALTER TABLE appointments ADD COLUMN client_name VARCHAR(100);
+UPDATE appointments a SET client_name = c.name
+FROM clients c WHERE a.client_id = c.id;
+ALTER TABLE appointments ALTER COLUMN client_name SET NOT NULL;
+DROP INDEX IF EXISTS idx_appointments_client_id;
+ALTER TABLE appointments DROP COLUMN client_id;
+
+-- Eliminar tabla de clientes
+DROP TABLE IF EXISTS clients;
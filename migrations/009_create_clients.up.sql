@@ -0,0 +1,43 @@
+-- Crear tabla de clientes
+CREATE TABLE clients (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR(100) NOT NULL,
+  phone VARCHAR(30),
+  email VARCHAR(150),
+  birthday DATE,
+  notes TEXT,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_clients_name ON clients(name);
+CREATE INDEX idx_clients_phone ON clients(phone);
+CREATE INDEX idx_clients_email ON clients(email);
+
+-- Crear un cliente por cada nombre distinto (sin importar mayusculas ni
+-- espacios) que aparezca en citas o en la lista de espera
+INSERT INTO clients (name, phone)
+SELECT MIN(name), MAX(phone)
+FROM (
+  SELECT TRIM(client_name) AS name, NULL::VARCHAR AS phone FROM appointments
+  UNION ALL
+  SELECT TRIM(client_name), NULLIF(TRIM(phone), '') FROM waitlist_entries
+) names
+GROUP BY LOWER(name);
+
+-- Vincular citas con su cliente
+ALTER TABLE appointments ADD COLUMN client_id INTEGER REFERENCES clients(id);
+UPDATE appointments a SET client_id = c.id
+FROM clients c WHERE LOWER(TRIM(a.client_name)) = LOWER(c.name);
+ALTER TABLE appointments ALTER COLUMN client_id SET NOT NULL;
+ALTER TABLE appointments DROP COLUMN client_name;
+CREATE INDEX idx_appointments_client_id ON appointments(client_id);
+
+-- Vincular lista de espera con su cliente
+ALTER TABLE waitlist_entries ADD COLUMN client_id INTEGER REFERENCES clients(id);
+UPDATE waitlist_entries w SET client_id = c.id
+FROM clients c WHERE LOWER(TRIM(w.client_name)) = LOWER(c.name);
+ALTER TABLE waitlist_entries ALTER COLUMN client_id SET NOT NULL;
+ALTER TABLE waitlist_entries DROP COLUMN client_name;
+ALTER TABLE waitlist_entries DROP COLUMN phone;
+CREATE INDEX idx_waitlist_entries_client_id ON waitlist_entries(client_id);
@@ -0,0 +1,2 @@
+-- Eliminar tabla de cargos
+DROP TABLE IF EXISTS fees;
@@ -0,0 +1,14 @@
+-- Crear tabla de cargos por cancelacion tardia e inasistencia
+CREATE TABLE fees (
+  id SERIAL PRIMARY KEY,
+  client_id INTEGER NOT NULL REFERENCES clients(id),
+  appointment_id INTEGER NOT NULL REFERENCES appointments(id),
+  reason VARCHAR(20) NOT NULL CHECK (reason IN ('late_cancel', 'no_show')),
+  percent INTEGER NOT NULL,
+  amount DECIMAL(10, 2) NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Indices para consultar los cargos por cliente y por cita
+CREATE INDEX idx_fees_client_id ON fees(client_id);
+CREATE INDEX idx_fees_appointment_id ON fees(appointment_id);
@@ -0,0 +1,4 @@
+-- Eliminar indice y columnas de sena
+DROP INDEX IF EXISTS idx_appointments_client_status;
+ALTER TABLE appointments DROP COLUMN IF EXISTS deposit_paid_at;
+ALTER TABLE appointments DROP COLUMN IF EXISTS deposit_amount;
@@ -0,0 +1,6 @@
+-- Sena exigida a clientes con inasistencias repetidas
+ALTER TABLE appointments ADD COLUMN deposit_amount DECIMAL(10, 2) NOT NULL DEFAULT 0;
+ALTER TABLE appointments ADD COLUMN deposit_paid_at TIMESTAMP;
+
+-- Indice para contar las inasistencias de cada cliente
+CREATE INDEX idx_appointments_client_status ON appointments(client_id, status, start_time);
@@ -0,0 +1,2 @@
+-- Eliminar marca de anonimizacion
+ALTER TABLE clients DROP COLUMN IF EXISTS erased_at;
@@ -0,0 +1,2 @@
+-- Marca de clientes cuyos datos personales fueron anonimizados
+ALTER TABLE clients ADD COLUMN erased_at TIMESTAMP;
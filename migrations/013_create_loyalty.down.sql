@@ -0,0 +1,2 @@
+-- Eliminar libro de puntos
+DROP TABLE IF EXISTS loyalty_entries;
@@ -0,0 +1,14 @@
+-- Crear libro de puntos de fidelidad por cliente
+CREATE TABLE loyalty_entries (
+  id SERIAL PRIMARY KEY,
+  client_id INTEGER NOT NULL REFERENCES clients(id),
+  appointment_id INTEGER REFERENCES appointments(id),
+  kind VARCHAR(20) NOT NULL CHECK (kind IN ('earn', 'redeem', 'refund')),
+  points INTEGER NOT NULL,
+  amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Indices para calcular saldos y descuentos por cita
+CREATE INDEX idx_loyalty_entries_client_id ON loyalty_entries(client_id);
+CREATE INDEX idx_loyalty_entries_appointment_id ON loyalty_entries(appointment_id);
@@ -0,0 +1,3 @@
+-- Eliminar tablas de tarjetas de regalo
+DROP TABLE IF EXISTS voucher_transactions;
+DROP TABLE IF EXISTS vouchers;
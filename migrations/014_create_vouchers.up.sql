@@ -0,0 +1,23 @@
+-- Crear tabla de tarjetas de regalo
+CREATE TABLE vouchers (
+  id SERIAL PRIMARY KEY,
+  code VARCHAR(32) NOT NULL UNIQUE,
+  initial_balance DECIMAL(10, 2) NOT NULL CHECK (initial_balance > 0),
+  balance DECIMAL(10, 2) NOT NULL CHECK (balance >= 0),
+  expires_at TIMESTAMP,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear tabla de canjes y devoluciones de saldo por cita
+CREATE TABLE voucher_transactions (
+  id SERIAL PRIMARY KEY,
+  voucher_id INTEGER NOT NULL REFERENCES vouchers(id),
+  appointment_id INTEGER NOT NULL REFERENCES appointments(id),
+  kind VARCHAR(20) NOT NULL CHECK (kind IN ('redeem', 'refund')),
+  amount DECIMAL(10, 2) NOT NULL CHECK (amount > 0),
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_voucher_transactions_voucher_id ON voucher_transactions(voucher_id);
+CREATE INDEX idx_voucher_transactions_appointment_id ON voucher_transactions(appointment_id);
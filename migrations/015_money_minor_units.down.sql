@@ -0,0 +1,43 @@
+-- Volver a importes DECIMAL(10,2). Se pierde la moneda de cada importe.
+
+ALTER TABLE voucher_transactions ADD COLUMN amount DECIMAL(10, 2);
+UPDATE voucher_transactions SET amount = amount_cents / 100.0;
+ALTER TABLE voucher_transactions ALTER COLUMN amount SET NOT NULL;
+ALTER TABLE voucher_transactions ADD CHECK (amount > 0);
+ALTER TABLE voucher_transactions DROP COLUMN amount_cents;
+ALTER TABLE voucher_transactions DROP COLUMN amount_currency;
+
+ALTER TABLE vouchers ADD COLUMN initial_balance DECIMAL(10, 2);
+ALTER TABLE vouchers ADD COLUMN balance DECIMAL(10, 2);
+UPDATE vouchers SET initial_balance = initial_balance_cents / 100.0,
+  balance = balance_cents / 100.0;
+ALTER TABLE vouchers ALTER COLUMN initial_balance SET NOT NULL;
+ALTER TABLE vouchers ALTER COLUMN balance SET NOT NULL;
+ALTER TABLE vouchers ADD CHECK (initial_balance > 0);
+ALTER TABLE vouchers ADD CHECK (balance >= 0);
+ALTER TABLE vouchers DROP COLUMN initial_balance_cents;
+ALTER TABLE vouchers DROP COLUMN initial_balance_currency;
+ALTER TABLE vouchers DROP COLUMN balance_cents;
+ALTER TABLE vouchers DROP COLUMN balance_currency;
+
+ALTER TABLE loyalty_entries ADD COLUMN amount DECIMAL(10, 2) NOT NULL DEFAULT 0;
+UPDATE loyalty_entries SET amount = amount_cents / 100.0;
+ALTER TABLE loyalty_entries DROP COLUMN amount_cents;
+ALTER TABLE loyalty_entries DROP COLUMN amount_currency;
+
+ALTER TABLE fees ADD COLUMN amount DECIMAL(10, 2);
+UPDATE fees SET amount = amount_cents / 100.0;
+ALTER TABLE fees ALTER COLUMN amount SET NOT NULL;
+ALTER TABLE fees DROP COLUMN amount_cents;
+ALTER TABLE fees DROP COLUMN amount_currency;
+
+ALTER TABLE appointments ADD COLUMN deposit_amount DECIMAL(10, 2) NOT NULL DEFAULT 0;
+UPDATE appointments SET deposit_amount = deposit_cents / 100.0;
+ALTER TABLE appointments DROP COLUMN deposit_cents;
+ALTER TABLE appointments DROP COLUMN deposit_currency;
+
+ALTER TABLE products ADD COLUMN price DECIMAL(10, 2);
+UPDATE products SET price = price_cents / 100.0;
+ALTER TABLE products ALTER COLUMN price SET NOT NULL;
+ALTER TABLE products DROP COLUMN price_cents;
+ALTER TABLE products DROP COLUMN price_currency;
@@ -0,0 +1,55 @@
+-- Pasar los importes de DECIMAL(10,2) a centavos enteros con moneda explicita.
+-- DECIMAL(10,2) tiene exactamente dos decimales, la conversion no pierde datos.
+-- Los importes existentes estan en pesos colombianos (COP).
+
+-- Precios de productos
+ALTER TABLE products ADD COLUMN price_cents BIGINT;
+ALTER TABLE products ADD COLUMN price_currency VARCHAR(3) NOT NULL DEFAULT 'COP';
+UPDATE products SET price_cents = ROUND(price * 100)::BIGINT;
+ALTER TABLE products ALTER COLUMN price_cents SET NOT NULL;
+ALTER TABLE products ADD CONSTRAINT products_price_cents_positive CHECK (price_cents > 0);
+ALTER TABLE products DROP COLUMN price;
+
+-- Senas de citas
+ALTER TABLE appointments ADD COLUMN deposit_cents BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE appointments ADD COLUMN deposit_currency VARCHAR(3) NOT NULL DEFAULT '';
+UPDATE appointments SET deposit_cents = ROUND(deposit_amount * 100)::BIGINT,
+  deposit_currency = 'COP'
+WHERE deposit_amount <> 0;
+ALTER TABLE appointments DROP COLUMN deposit_amount;
+
+-- Cargos por cancelacion
+ALTER TABLE fees ADD COLUMN amount_cents BIGINT;
+ALTER TABLE fees ADD COLUMN amount_currency VARCHAR(3) NOT NULL DEFAULT 'COP';
+UPDATE fees SET amount_cents = ROUND(amount * 100)::BIGINT;
+ALTER TABLE fees ALTER COLUMN amount_cents SET NOT NULL;
+ALTER TABLE fees DROP COLUMN amount;
+
+-- Canjes de puntos
+ALTER TABLE loyalty_entries ADD COLUMN amount_cents BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE loyalty_entries ADD COLUMN amount_currency VARCHAR(3) NOT NULL DEFAULT '';
+UPDATE loyalty_entries SET amount_cents = ROUND(amount * 100)::BIGINT,
+  amount_currency = 'COP'
+WHERE amount <> 0;
+ALTER TABLE loyalty_entries DROP COLUMN amount;
+
+-- Tarjetas de regalo
+ALTER TABLE vouchers ADD COLUMN initial_balance_cents BIGINT;
+ALTER TABLE vouchers ADD COLUMN initial_balance_currency VARCHAR(3) NOT NULL DEFAULT 'COP';
+ALTER TABLE vouchers ADD COLUMN balance_cents BIGINT;
+ALTER TABLE vouchers ADD COLUMN balance_currency VARCHAR(3) NOT NULL DEFAULT 'COP';
+UPDATE vouchers SET initial_balance_cents = ROUND(initial_balance * 100)::BIGINT,
+  balance_cents = ROUND(balance * 100)::BIGINT;
+ALTER TABLE vouchers ALTER COLUMN initial_balance_cents SET NOT NULL;
+ALTER TABLE vouchers ALTER COLUMN balance_cents SET NOT NULL;
+ALTER TABLE vouchers ADD CONSTRAINT vouchers_initial_balance_cents_positive CHECK (initial_balance_cents > 0);
+ALTER TABLE vouchers ADD CONSTRAINT vouchers_balance_cents_non_negative CHECK (balance_cents >= 0);
+ALTER TABLE vouchers DROP COLUMN initial_balance;
+ALTER TABLE vouchers DROP COLUMN balance;
+
+ALTER TABLE voucher_transactions ADD COLUMN amount_cents BIGINT;
+ALTER TABLE voucher_transactions ADD COLUMN amount_currency VARCHAR(3) NOT NULL DEFAULT 'COP';
+UPDATE voucher_transactions SET amount_cents = ROUND(amount * 100)::BIGINT;
+ALTER TABLE voucher_transactions ALTER COLUMN amount_cents SET NOT NULL;
+ALTER TABLE voucher_transactions ADD CONSTRAINT voucher_transactions_amount_cents_positive CHECK (amount_cents > 0);
+ALTER TABLE voucher_transactions DROP COLUMN amount;
@@ -0,0 +1,13 @@
+CREATE TABLE appointment_products (
+  appointment_id INTEGER REFERENCES appointments(id) ON DELETE CASCADE,
+  product_id INTEGER REFERENCES products(id) ON DELETE CASCADE,
+  PRIMARY KEY (appointment_id, product_id)
+);
+
+-- Se pierden las cantidades y precios registrados en las lineas
+INSERT INTO appointment_products (appointment_id, product_id)
+SELECT DISTINCT ai.appointment_id, ai.product_id
+FROM appointment_items ai
+JOIN products p ON p.id = ai.product_id;
+
+DROP TABLE appointment_items;
@@ -0,0 +1,26 @@
+-- Lineas de la cita con el nombre y precio del producto al momento de agendar.
+-- product_id queda solo como referencia: borrar o cambiar el producto no altera
+-- los totales de citas ya registradas.
+CREATE TABLE appointment_items (
+  id SERIAL PRIMARY KEY,
+  appointment_id INTEGER NOT NULL REFERENCES appointments(id) ON DELETE CASCADE,
+  product_id INTEGER NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  unit_price_cents BIGINT NOT NULL CHECK (unit_price_cents >= 0),
+  unit_price_currency VARCHAR(3) NOT NULL,
+  quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity > 0),
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_appointment_items_appointment_id ON appointment_items(appointment_id);
+CREATE INDEX idx_appointment_items_product_id ON appointment_items(product_id);
+
+-- Las citas existentes toman el precio actual del producto
+INSERT INTO appointment_items (appointment_id, product_id, name, unit_price_cents, unit_price_currency, quantity, created_at)
+SELECT ap.appointment_id, p.id, p.name, p.price_cents, p.price_currency, 1, a.created_at
+FROM appointment_products ap
+JOIN products p ON p.id = ap.product_id
+JOIN appointments a ON a.id = ap.appointment_id
+ORDER BY ap.appointment_id, p.id;
+
+DROP TABLE appointment_products;
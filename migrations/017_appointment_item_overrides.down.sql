@@ -0,0 +1,7 @@
+ALTER TABLE appointment_items DROP COLUMN override_reason;
+ALTER TABLE appointment_items DROP COLUMN list_price_currency;
+ALTER TABLE appointment_items DROP COLUMN list_price_cents;
+
+-- Las lineas libres no tienen producto al que volver
+DELETE FROM appointment_items WHERE product_id IS NULL;
+ALTER TABLE appointment_items ALTER COLUMN product_id SET NOT NULL;
@@ -0,0 +1,7 @@
+-- Lineas libres sin producto y precios ajustados por linea
+ALTER TABLE appointment_items ALTER COLUMN product_id DROP NOT NULL;
+
+-- Precio de lista cuando se cobro otro precio, con el motivo del ajuste
+ALTER TABLE appointment_items ADD COLUMN list_price_cents BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE appointment_items ADD COLUMN list_price_currency VARCHAR(3) NOT NULL DEFAULT '';
+ALTER TABLE appointment_items ADD COLUMN override_reason VARCHAR(200) NOT NULL DEFAULT '';
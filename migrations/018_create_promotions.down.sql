@@ -0,0 +1,8 @@
+-- Eliminar promociones y categorias de productos
+DROP TABLE IF EXISTS promotion_redemptions;
+DROP TABLE IF EXISTS promotions;
+
+ALTER TABLE appointment_items DROP COLUMN category;
+
+DROP INDEX IF EXISTS idx_products_category;
+ALTER TABLE products DROP COLUMN category;
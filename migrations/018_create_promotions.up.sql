@@ -0,0 +1,45 @@
+-- Categoria de productos para acotar promociones
+ALTER TABLE products ADD COLUMN category VARCHAR(50) NOT NULL DEFAULT '';
+CREATE INDEX idx_products_category ON products(category);
+
+-- Las lineas guardan la categoria del producto al agendar
+ALTER TABLE appointment_items ADD COLUMN category VARCHAR(50) NOT NULL DEFAULT '';
+UPDATE appointment_items ai SET category = p.category
+FROM products p WHERE p.id = ai.product_id;
+
+-- Codigos promocionales con descuento porcentual o fijo
+CREATE TABLE promotions (
+  id SERIAL PRIMARY KEY,
+  code VARCHAR(32) NOT NULL UNIQUE,
+  description VARCHAR(200) NOT NULL DEFAULT '',
+  kind VARCHAR(20) NOT NULL CHECK (kind IN ('percent', 'fixed')),
+  percent INTEGER NOT NULL DEFAULT 0 CHECK (percent BETWEEN 0 AND 100),
+  amount_cents BIGINT NOT NULL DEFAULT 0 CHECK (amount_cents >= 0),
+  amount_currency VARCHAR(3) NOT NULL DEFAULT '',
+  product_id INTEGER REFERENCES products(id) ON DELETE SET NULL,
+  category VARCHAR(50) NOT NULL DEFAULT '',
+  valid_from TIMESTAMP,
+  valid_until TIMESTAMP,
+  weekdays TEXT,
+  max_uses INTEGER NOT NULL DEFAULT 0 CHECK (max_uses >= 0),
+  max_uses_per_client INTEGER NOT NULL DEFAULT 0 CHECK (max_uses_per_client >= 0),
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Usos de promociones en citas. Los usos liberados no cuentan en los limites.
+CREATE TABLE promotion_redemptions (
+  id SERIAL PRIMARY KEY,
+  promotion_id INTEGER NOT NULL REFERENCES promotions(id),
+  appointment_id INTEGER NOT NULL REFERENCES appointments(id) ON DELETE CASCADE,
+  client_id INTEGER NOT NULL REFERENCES clients(id),
+  released_at TIMESTAMP,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_promotion_redemptions_promotion_id ON promotion_redemptions(promotion_id);
+CREATE INDEX idx_promotion_redemptions_client_id ON promotion_redemptions(client_id);
+
+-- Una sola promocion vigente por cita
+CREATE UNIQUE INDEX idx_promotion_redemptions_appointment_id ON promotion_redemptions(appointment_id) WHERE released_at IS NULL;
@@ -0,0 +1,9 @@
+-- Eliminar impuestos de lineas y productos
+ALTER TABLE appointment_items DROP COLUMN tax_exempt;
+ALTER TABLE appointment_items DROP COLUMN tax_included;
+ALTER TABLE appointment_items DROP COLUMN tax_rate;
+
+ALTER TABLE products DROP CONSTRAINT products_tax_exempt_rate;
+ALTER TABLE products DROP COLUMN tax_exempt;
+ALTER TABLE products DROP COLUMN tax_included;
+ALTER TABLE products DROP COLUMN tax_rate;
@@ -0,0 +1,12 @@
+-- Impuesto (IVA) por producto en puntos basicos (1900 = 19%), con precio
+-- que incluye o no el impuesto y marca de exento
+ALTER TABLE products ADD COLUMN tax_rate INTEGER NOT NULL DEFAULT 0 CHECK (tax_rate BETWEEN 0 AND 10000);
+ALTER TABLE products ADD COLUMN tax_included BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE products ADD COLUMN tax_exempt BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE products ADD CONSTRAINT products_tax_exempt_rate CHECK (NOT tax_exempt OR tax_rate = 0);
+
+-- Las lineas guardan el impuesto del producto al agendar. Las citas
+-- existentes quedan sin impuesto, como se cobraron.
+ALTER TABLE appointment_items ADD COLUMN tax_rate INTEGER NOT NULL DEFAULT 0 CHECK (tax_rate BETWEEN 0 AND 10000);
+ALTER TABLE appointment_items ADD COLUMN tax_included BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE appointment_items ADD COLUMN tax_exempt BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+-- Eliminar recibos
+DROP TABLE IF EXISTS receipts;
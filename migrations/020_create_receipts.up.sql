@@ -0,0 +1,15 @@
+-- Recibos de citas completadas. El numero se asigna en la misma transaccion
+-- que bloquea la tabla, para que la numeracion sea consecutiva y sin huecos;
+-- por eso no se usa una secuencia. Lo impreso (nombres, inicio de la cita y
+-- total) se guarda al emitirlo para que no cambie con ajustes posteriores.
+CREATE TABLE receipts (
+  id SERIAL PRIMARY KEY,
+  number BIGINT NOT NULL UNIQUE CHECK (number > 0),
+  appointment_id INTEGER NOT NULL UNIQUE REFERENCES appointments(id) ON DELETE RESTRICT,
+  issued_at TIMESTAMP NOT NULL,
+  client_name VARCHAR(100),
+  barber_name VARCHAR(100),
+  appointment_start TIMESTAMP NOT NULL,
+  total TEXT NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
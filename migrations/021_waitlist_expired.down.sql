@@ -0,0 +1,5 @@
+-- Volver a los estados originales; las vencidas quedan como canceladas
+UPDATE waitlist_entries SET status = 'cancelled' WHERE status = 'expired';
+ALTER TABLE waitlist_entries DROP CONSTRAINT waitlist_entries_status_check;
+ALTER TABLE waitlist_entries ADD CONSTRAINT waitlist_entries_status_check
+  CHECK (status IN ('waiting', 'offered', 'accepted', 'cancelled'));
@@ -0,0 +1,4 @@
+-- Las solicitudes cuya ventana deseada ya paso se marcan como vencidas
+ALTER TABLE waitlist_entries DROP CONSTRAINT waitlist_entries_status_check;
+ALTER TABLE waitlist_entries ADD CONSTRAINT waitlist_entries_status_check
+  CHECK (status IN ('waiting', 'offered', 'accepted', 'cancelled', 'expired'));
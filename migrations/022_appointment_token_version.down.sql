@@ -0,0 +1,2 @@
+-- Quitar la version del token de gestion
+ALTER TABLE appointments DROP COLUMN IF EXISTS token_version;
@@ -0,0 +1,3 @@
+-- Version del token de gestion: al reprogramar se incrementa y los tokens
+-- anteriores dejan de ser validos
+ALTER TABLE appointments ADD COLUMN token_version INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Prueba de concurrencia: envia muchas reservas simultaneas para el mismo
+// barbero y horario contra un servidor en ejecucion. Solo una debe crearse,
+// el resto debe ser rechazada por solapamiento.
+//
+//	go run ./scripts/concurrency -client 1 -barber 1 -start 2030-01-07T10:00:00-05:00 -product 1
+func main() {
+	apiURL := flag.String("url", "http://localhost:8080/api/v1", "URL base de la API")
+	clientID := flag.Uint("client", 1, "ID del cliente")
+	barberID := flag.Uint("barber", 1, "ID del barbero")
+	productID := flag.Uint("product", 1, "ID del producto a reservar")
+	start := flag.String("start", "", "hora de inicio en RFC3339, debe estar libre")
+	workers := flag.Int("n", 50, "cantidad de reservas simultaneas")
+	flag.Parse()
+
+	if _, err := time.Parse(time.RFC3339, *start); err != nil {
+		log.Fatalf("start invalido, use RFC3339: %v", err)
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"client_id":  *clientID,
+		"barber_id":  *barberID,
+		"start_time": *start,
+		"products":   []uint{*productID},
+	})
+	if err != nil {
+		log.Fatalf("Error armando la solicitud: %v", err)
+	}
+
+	// Todas las solicitudes esperan la misma señal para salir a la vez
+	var wg sync.WaitGroup
+	ready := make(chan struct{})
+	statuses := make(chan int, *workers)
+
+	for i := 0; i < *workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-ready
+
+			resp, err := http.Post(*apiURL+"/appointments", "application/json", bytes.NewReader(body))
+			if err != nil {
+				log.Printf("Error en la solicitud: %v", err)
+				statuses <- 0
+				return
+			}
+			resp.Body.Close()
+			statuses <- resp.StatusCode
+		}()
+	}
+
+	close(ready)
+	wg.Wait()
+	close(statuses)
+
+	counts := map[int]int{}
+	for status := range statuses {
+		counts[status]++
+	}
+
+	fmt.Printf("Creadas: %d, rechazadas por solapamiento: %d, otras: %d\n",
+		counts[http.StatusCreated], counts[http.StatusConflict],
+		*workers-counts[http.StatusCreated]-counts[http.StatusConflict])
+
+	if counts[http.StatusCreated] != 1 || counts[http.StatusConflict] != *workers-1 {
+		fmt.Println("FALLO: se esperaba exactamente una reserva creada")
+		os.Exit(1)
+	}
+
+	fmt.Println("OK: el horario se reservo una sola vez")
+}
@@ -1,4 +1,4 @@
-package seed
+package main
 
 import (
 	"log"
@@ -38,19 +38,29 @@ func main() {
 		Logger: logger.Default.LogMode(logger.Info),
 	})
 	if err != nil {
-		log.Fatal("Error conectando a la base de datos: %v", err)
+		log.Fatalf("Error conectando a la base de datos: %v", err)
 	}
 
 	log.Printf("Conexion establecido")
 
 	// Ejecutar migraciones si es necesario
-	if err := db.AutoMigrate(&domain.Appointment{}, &domain.Product{}); err != nil {
-		log.Fatalf("Error en migraciones: &v", err)
+	if err := db.AutoMigrate(&domain.Client{}, &domain.Barber{}, &domain.AppointmentSeries{}, &domain.Appointment{}, &domain.AppointmentItem{}, &domain.Product{}, &domain.BusinessHours{}, &domain.CalendarException{}, &domain.WaitlistEntry{}, &domain.Fee{}, &domain.LoyaltyEntry{}, &domain.Voucher{}, &domain.VoucherTransaction{}, &domain.Promotion{}, &domain.PromotionRedemption{}, &domain.Receipt{}); err != nil {
+		log.Fatalf("Error en migraciones: %v", err)
 	}
 
 	// Poblar Productos
 	if err := seedProducts(db); err != nil {
-		log.Fatal("Error poblando productos: %v", err)
+		log.Fatalf("Error poblando productos: %v", err)
+	}
+
+	// Poblar barberos
+	if err := seedBarbers(db); err != nil {
+		log.Fatalf("Error poblando barberos: %v", err)
+	}
+
+	// Poblar horario de atencion
+	if err := seedBusinessHours(db); err != nil {
+		log.Fatalf("Error poblando horario de atencion: %v", err)
 	}
 
 	// Poblar citas de ejemplo
@@ -66,44 +76,68 @@ func seedProducts(db *gorm.DB) error {
 
 	products := []domain.Product{
 		{
-			Name:        "Corte de cabello",
-			Price:       15000.00,
-			Description: "Corte tradicional de cabello con tijera y máquina",
+			Name:            "Corte de cabello",
+			Category:        "cortes",
+			Price:           domain.NewMoney(1500000, "COP"),
+			Description:     "Corte tradicional de cabello con tijera y máquina",
+			DurationMinutes: 45,
+			BufferMinutes:   10,
 		},
 		{
-			Name:        "Corte + Barba",
-			Price:       25000.00,
-			Description: "Corte de cabello completo más arreglo de barba",
+			Name:            "Corte + Barba",
+			Category:        "cortes",
+			Price:           domain.NewMoney(2500000, "COP"),
+			Description:     "Corte de cabello completo más arreglo de barba",
+			DurationMinutes: 75,
+			BufferMinutes:   10,
 		},
 		{
-			Name:        "Afeitado clásico",
-			Price:       18000.00,
-			Description: "Afeitado tradicional con navaja y toalla caliente",
+			Name:            "Afeitado clásico",
+			Category:        "barba",
+			Price:           domain.NewMoney(1800000, "COP"),
+			Description:     "Afeitado tradicional con navaja y toalla caliente",
+			DurationMinutes: 40,
+			BufferMinutes:   5,
 		},
 		{
-			Name:        "Lavado de cabello",
-			Price:       8000.00,
-			Description: "Lavado y masaje capilar con productos premium",
+			Name:            "Lavado de cabello",
+			Category:        "cuidado",
+			Price:           domain.NewMoney(800000, "COP"),
+			Description:     "Lavado y masaje capilar con productos premium",
+			DurationMinutes: 20,
+			BufferMinutes:   5,
 		},
 		{
-			Name:        "Peinado especial",
-			Price:       12000.00,
-			Description: "Peinado para eventos especiales con fijadores",
+			Name:            "Peinado especial",
+			Category:        "cuidado",
+			Price:           domain.NewMoney(1200000, "COP"),
+			Description:     "Peinado para eventos especiales con fijadores",
+			DurationMinutes: 30,
+			BufferMinutes:   5,
 		},
 		{
-			Name:        "Tratamiento capilar",
-			Price:       30000.00,
-			Description: "Tratamiento nutritivo y reparador para el cabello",
+			Name:            "Tratamiento capilar",
+			Category:        "cuidado",
+			Price:           domain.NewMoney(3000000, "COP"),
+			Description:     "Tratamiento nutritivo y reparador para el cabello",
+			DurationMinutes: 60,
+			BufferMinutes:   10,
 		},
 		{
-			Name:        "Corte infantil",
-			Price:       12000.00,
-			Description: "Corte de cabello especializado para niños",
+			Name:            "Corte infantil",
+			Category:        "cortes",
+			Price:           domain.NewMoney(1200000, "COP"),
+			Description:     "Corte de cabello especializado para niños",
+			DurationMinutes: 30,
+			BufferMinutes:   10,
 		},
 		{
-			Name:        "Diseño en barba",
-			Price:       20000.00,
-			Description: "Diseño y perfilado artístico de barba",
+			Name:            "Diseño en barba",
+			Category:        "barba",
+			Price:           domain.NewMoney(2000000, "COP"),
+			Description:     "Diseño y perfilado artístico de barba",
+			DurationMinutes: 40,
+			BufferMinutes:   5,
 		},
 	}
 
@@ -116,7 +150,7 @@ func seedProducts(db *gorm.DB) error {
 			if err := db.Create(&product).Error; err != nil {
 				return err
 			}
-			log.Printf("Producto creado: %s - $%.2f", product.Name, product.Price)
+			log.Printf("Producto creado: %s - %s", product.Name, product.Price)
 		} else {
 			log.Printf("Producto ya existe: %s", product.Name)
 		}
@@ -126,6 +160,56 @@ func seedProducts(db *gorm.DB) error {
 	return nil
 }
 
+func seedBarbers(db *gorm.DB) error {
+	log.Println("Poblando barberos...")
+
+	barbers := []domain.Barber{
+		{Name: "Andrés", Phone: "3001234567", Active: true},
+		{Name: "Camilo", Phone: "3007654321", Active: true},
+		{Name: "Santiago", Phone: "3015556677", Active: true},
+	}
+
+	for _, barber := range barbers {
+		// Verificar si el barbero ya existe
+		var existingBarber domain.Barber
+		result := db.Where("name = ?", barber.Name).First(&existingBarber)
+		if result.Error == gorm.ErrRecordNotFound {
+			if err := db.Create(&barber).Error; err != nil {
+				return err
+			}
+			log.Printf("Barbero creado: %s", barber.Name)
+		} else {
+			log.Printf("Barbero ya existe: %s", barber.Name)
+		}
+	}
+
+	log.Println("Barberos poblados exitosamente")
+	return nil
+}
+
+func seedBusinessHours(db *gorm.DB) error {
+	log.Println("Poblando horario de atencion...")
+
+	for weekday := 0; weekday <= 6; weekday++ {
+		// Domingo cerrado, lunes a sabado de 09:00 a 19:00
+		hours := domain.BusinessHours{Weekday: weekday, OpenTime: "09:00", CloseTime: "19:00"}
+		if weekday == 0 {
+			hours = domain.BusinessHours{Weekday: weekday, Closed: true}
+		}
+
+		var existingHours domain.BusinessHours
+		result := db.Where("weekday = ?", weekday).First(&existingHours)
+		if result.Error == gorm.ErrRecordNotFound {
+			if err := db.Create(&hours).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	log.Println("Horario de atencion poblado exitosamente")
+	return nil
+}
+
 func seedAppointments(db *gorm.DB) error {
 	log.Println("Poblando citas de ejemplo...")
 
@@ -140,6 +224,16 @@ func seedAppointments(db *gorm.DB) error {
 		return nil
 	}
 
+	var barbers []domain.Barber
+	if err := db.Order("id").Find(&barbers).Error; err != nil {
+		return err
+	}
+
+	if len(barbers) == 0 {
+		log.Println("No hay barberos disponibles para crear citas")
+		return nil
+	}
+
 	// Crear citas de ejemplo
 	now := time.Now()
 
@@ -169,41 +263,48 @@ func seedAppointments(db *gorm.DB) error {
 		)
 		endTime := startTime.Add(time.Duration(apptData.duration) * time.Minute)
 
+		// Buscar o crear el cliente
+		client := domain.Client{Name: apptData.clientName}
+		if err := db.Where("name = ?", apptData.clientName).FirstOrCreate(&client).Error; err != nil {
+			return err
+		}
+
 		// Verificar si la cita ya existe
 		var existingAppointment domain.Appointment
-		result := db.Where("cliente_name = ? AND start_time = ?",
-			apptData.clientName, startTime).First(&existingAppointment)
+		result := db.Where("client_id = ? AND start_time = ?",
+			client.ID, startTime).First(&existingAppointment)
 
 		if result.Error == gorm.ErrRecordNotFound {
-			// Crear la cita
-			appointment := domain.Appointment{
-				ClienteName: apptData.clientName,
-				StartTime:   startTime,
-				EndTime:     endTime,
-			}
-
-			// Crear la cita primero
-			if err := db.Create(&appointment).Error; err != nil {
-				return err
-			}
-
-			// Asociar productos usando la relación many2many
-			var selectedProducts []domain.Product
+			// Lineas de la cita con el precio actual de cada producto
+			var items []domain.AppointmentItem
 			for _, productIdx := range apptData.products {
 				if productIdx < len(products) {
-					selectedProducts = append(selectedProducts, products[productIdx])
+					items = append(items, domain.AppointmentItem{
+						ProductID: &products[productIdx].ID,
+						Name:      products[productIdx].Name,
+						Category:  products[productIdx].Category,
+						Tax:       products[productIdx].Tax,
+						UnitPrice: products[productIdx].Price,
+						Quantity:  1,
+					})
 				}
 			}
 
-			// Asociar los productos a la cita
-			if len(selectedProducts) > 0 {
-				if err := db.Model(&appointment).Association("Products").Append(selectedProducts); err != nil {
-					log.Printf("Error asociando productos a la cita: %v", err)
-				}
+			// Crear la cita junto con sus lineas
+			appointment := domain.Appointment{
+				ClientID:  client.ID,
+				BarberID:  barbers[i%len(barbers)].ID,
+				StartTime: startTime,
+				EndTime:   endTime,
+				Items:     items,
+			}
+
+			if err := db.Create(&appointment).Error; err != nil {
+				return err
 			}
 
 			log.Printf("Cita creada: %s - %s a %s",
-				appointment.ClienteName,
+				client.Name,
 				appointment.StartTime.Format("2006-01-02 15:04"),
 				appointment.EndTime.Format("15:04"))
 		} else {